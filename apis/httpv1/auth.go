@@ -0,0 +1,22 @@
+package httpv1
+
+import "github.com/ninehills/go-webapp-template/internal/entity"
+
+type LoginRequest struct {
+	Username string `binding:"required,username,min=1,max=64" json:"username"`
+	Password string `binding:"required"                       json:"password"`
+}
+
+type LoginResponse entity.Token
+
+type RefreshRequest struct {
+	RefreshToken string `binding:"required" json:"refreshToken"`
+}
+
+type RefreshResponse entity.Token
+
+type LogoutRequest struct {
+	RefreshToken string `binding:"required" json:"refreshToken"`
+}
+
+type LogoutResponse struct{}
@@ -14,6 +14,18 @@ type UpdateUserRequest struct {
 
 type UpdateUserResponse entity.User
 
+// PatchUserRequest 是 JSON Merge Patch（RFC 7396）的请求体，缺省的字段不修改，
+// description 为 null 或空字符串时清空，其他字段不允许为 null.
+type PatchUserRequest struct {
+	Status          *int32  `binding:"omitempty,oneof=1 2"          json:"status,omitempty"`
+	Email           *string `binding:"omitempty,min=1,max=64,email" json:"email,omitempty"`
+	Description     *string `binding:"omitempty,max=140"            json:"description,omitempty"`
+	Password        *string `binding:"omitempty"                    json:"password,omitempty"` // 密码有单独的方法进行校验
+	ConfirmPassword *string `binding:"omitempty"                    json:"confirmPassword,omitempty"`
+}
+
+type PatchUserResponse entity.User
+
 type ListUserRequest struct {
 	PageNo   int64  `binding:"gte=1"                                                form:"pageNo,default=1"`
 	PageSize int64  `binding:"gte=1"                                                form:"pageSize,default=100"`
@@ -22,6 +34,8 @@ type ListUserRequest struct {
 	Username string `binding:"omitempty,min=1,max=64,username"                      form:"username,default="`
 	Status   int32  `binding:"omitempty,oneof=1 2"                                  form:"status,default=0"`
 	Email    string `binding:"omitempty,min=1,max=64,email"                         form:"email,default="`
+	// 包含已删除的用户，需要 user:list-deleted 权限
+	IncludeDeleted bool `form:"includeDeleted,default=false"`
 }
 
 type ListUserResponse struct {
@@ -44,3 +58,5 @@ type CreateUserRequest struct {
 type CreateUserResponse entity.User
 
 type DeleteUserResponse struct{}
+
+type RestoreUserResponse entity.User
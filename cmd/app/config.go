@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ninehills/go-webapp-template/config"
+)
+
+func newConfigCmd(cfgFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Config file related commands",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "validate",
+			Short: "Load the config file and environment variables, then validate it",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := config.LoadConfig(*cfgFile)
+				if err != nil {
+					return err
+				}
+
+				if err = cfg.Validate(); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "config %s is valid\n", *cfgFile)
+
+				return nil
+			},
+		},
+		newConfigPrintCmd(cfgFile),
+	)
+
+	return cmd
+}
+
+func newConfigPrintCmd(cfgFile *string) *cobra.Command {
+	var redacted bool
+
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the effective config (config file merged with environment variables)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig(*cfgFile)
+			if err != nil {
+				return err
+			}
+
+			if redacted {
+				cfg = cfg.Redacted()
+			}
+
+			enc := yaml.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent(2)
+
+			return enc.Encode(cfg)
+		},
+	}
+	cmd.Flags().BoolVar(&redacted, "redacted", false, "hide passwords, secrets and DSNs")
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ninehills/go-webapp-template/config"
+)
+
+const healthcheckPath = "/livez"
+
+// 用于 Docker HEALTHCHECK，服务不健康时返回非 0.
+func newHealthcheckCmd(cfgFile *string) *cobra.Command {
+	var (
+		url     string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Probe the local http server, exit 1 when it is unhealthy",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if url == "" {
+				cfg, err := config.LoadConfig(*cfgFile)
+				if err != nil {
+					return err
+				}
+
+				url = "http://" + net.JoinHostPort("127.0.0.1", cfg.HTTP.Port) + healthcheckPath
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("healthcheck %s failed: %w", url, err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("healthcheck %s failed: status %d", url, resp.StatusCode)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&url, "url", "", "probe url, default http://127.0.0.1:<http.port>"+healthcheckPath)
+	cmd.Flags().DurationVar(&timeout, "timeout", 3*time.Second, "probe timeout")
+
+	return cmd
+}
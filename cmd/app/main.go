@@ -1,31 +1,45 @@
 package main
 
 import (
-	"flag"
-	"log"
 	"os"
 
-	"github.com/ninehills/go-webapp-template/internal/app"
+	"github.com/spf13/cobra"
+
 	"github.com/ninehills/go-webapp-template/pkg/version"
 )
 
 const defaultCfgFile = "./config/config.yml"
 
 func main() {
-	v := flag.Bool("v", false, "print version")
-	if *v {
-		log.Println(version.GetVersion().String())
-		os.Exit(0)
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
 	}
+}
+
+func newRootCmd() *cobra.Command {
+	var cfgFile string
 
-	h := flag.Bool("h", false, "print help")
-	if *h {
-		log.Printf("Usage: %s [-v] [-h] [-c config file]\n", os.Args[0])
-		os.Exit(0)
+	root := &cobra.Command{
+		Use:          "go-webapp-template",
+		Short:        "Go webapp template server and management commands",
+		Version:      version.GetVersion().String(),
+		SilenceUsage: true,
+		// 不带子命令时启动服务，兼容旧的启动方式
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cfgFile)
+		},
 	}
+	root.SetVersionTemplate("{{.Version}}\n")
+	root.PersistentFlags().StringVarP(&cfgFile, "config", "c", defaultCfgFile, "config file")
 
-	cfgFile := flag.String("c", defaultCfgFile, "config file")
-	flag.Parse()
+	root.AddCommand(
+		newServeCmd(&cfgFile),
+		newVersionCmd(),
+		newConfigCmd(&cfgFile),
+		newUserCmd(&cfgFile),
+		newMigrateCmd(&cfgFile),
+		newHealthcheckCmd(&cfgFile),
+	)
 
-	app.Run(*cfgFile)
+	return root
 }
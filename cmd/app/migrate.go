@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ninehills/go-webapp-template/internal/app"
+)
+
+func newMigrateCmd(cfgFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Database schema migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return app.Migrate(*cfgFile, []string{"up"})
+			},
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Rollback the latest applied migration",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return app.Migrate(*cfgFile, []string{"down"})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show migrations status",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return app.Migrate(*cfgFile, []string{"status"})
+			},
+		},
+		&cobra.Command{
+			Use:   "goto <version>",
+			Short: "Migrate up or down to the given version, 0 means rollback all",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return app.Migrate(*cfgFile, []string{"goto", args[0]})
+			},
+		},
+	)
+
+	return cmd
+}
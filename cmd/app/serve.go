@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ninehills/go-webapp-template/internal/app"
+)
+
+func newServeCmd(cfgFile *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the http server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(*cfgFile)
+		},
+	}
+}
+
+func serve(cfgFile string) error {
+	app.Run(cfgFile)
+
+	return nil
+}
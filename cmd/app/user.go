@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ninehills/go-webapp-template/config"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/password"
+)
+
+var errUsernameRequired = errors.New("--username is required")
+
+// 直接通过 service 层管理用户，不经过 HTTP 接口和权限校验.
+func newUserCmd(cfgFile *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users directly through the service layer",
+	}
+
+	cmd.AddCommand(
+		newUserCreateCmd(cfgFile),
+		newUserResetPasswordCmd(cfgFile),
+		newUserDisableCmd(cfgFile),
+		newUserListCmd(cfgFile),
+	)
+
+	return cmd
+}
+
+func newUserCreateCmd(cfgFile *string) *cobra.Command {
+	var (
+		in    entity.User
+		admin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an active user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if in.Username == "" {
+				return errUsernameRequired
+			}
+
+			if in.Email == "" {
+				in.Email = in.Username + "@example.com"
+			}
+
+			if err := password.ValidatePassword(in.Password, in.Password); err != nil {
+				return err
+			}
+
+			in.Status = entity.UserStatusActive
+
+			return withServices(*cfgFile, func(ctx context.Context, svcs *service.Services) error {
+				u, err := svcs.User.Create(ctx, in)
+				if err != nil {
+					return err
+				}
+
+				if admin {
+					if err = svcs.Authz.AssignRole(ctx, u.Username, entity.RoleAdmin); err != nil {
+						return err
+					}
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "user %s created\n", u.Username)
+
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&in.Username, "username", "", "username")
+	cmd.Flags().StringVar(&in.Email, "email", "", "email, default <username>@example.com")
+	cmd.Flags().StringVar(&in.Password, "password", "", "password")
+	cmd.Flags().StringVar(&in.Description, "description", "", "description")
+	cmd.Flags().BoolVar(&admin, "admin", false, "assign the admin role")
+
+	return cmd
+}
+
+func newUserResetPasswordCmd(cfgFile *string) *cobra.Command {
+	var username, pass string
+
+	cmd := &cobra.Command{
+		Use:   "reset-password",
+		Short: "Reset the password of a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username == "" {
+				return errUsernameRequired
+			}
+
+			if err := password.ValidatePassword(pass, pass); err != nil {
+				return err
+			}
+
+			return withServices(*cfgFile, func(ctx context.Context, svcs *service.Services) error {
+				_, err := svcs.User.Update(ctx, entity.User{Username: username, Password: pass})
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "password of user %s reset\n", username)
+
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&username, "username", "", "username")
+	cmd.Flags().StringVar(&pass, "password", "", "new password")
+
+	return cmd
+}
+
+func newUserDisableCmd(cfgFile *string) *cobra.Command {
+	var username string
+
+	cmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Disable a user, disabled users can not login",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username == "" {
+				return errUsernameRequired
+			}
+
+			return withServices(*cfgFile, func(ctx context.Context, svcs *service.Services) error {
+				_, err := svcs.User.Update(ctx, entity.User{Username: username, Status: entity.UserStatusInactive})
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "user %s disabled\n", username)
+
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&username, "username", "", "username")
+
+	return cmd
+}
+
+func newUserListCmd(cfgFile *string) *cobra.Command {
+	var p entity.PageQuery
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withServices(*cfgFile, func(ctx context.Context, svcs *service.Services) error {
+				result, users, err := svcs.User.Query(ctx, p, entity.OrderQuery{}, entity.UserQuery{})
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "USERNAME\tEMAIL\tSTATUS\tCREATED AT")
+
+				for _, u := range users {
+					status := "active"
+					if !u.IsActive() {
+						status = "disabled"
+					}
+
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Username, u.Email, status, u.CreatedAt.Format("2006-01-02 15:04:05"))
+				}
+
+				w.Flush()
+				fmt.Fprintf(cmd.OutOrStdout(), "page %d, %d of %d users\n", result.PageNo, len(users), result.TotalCount)
+
+				return nil
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&p.PageNo, "page", 1, "page number")
+	cmd.Flags().Int64Var(&p.PageSize, "size", 100, "page size")
+
+	return cmd
+}
+
+func withServices(cfgFile string, fn func(ctx context.Context, svcs *service.Services) error) error {
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	dep := dependency.NewDependency(cfg)
+	defer dep.Close()
+
+	return fn(context.Background(), service.NewServices(dep))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ninehills/go-webapp-template/pkg/version"
+)
+
+func newVersionCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := version.GetVersion()
+
+			switch format {
+			case "text":
+				fmt.Fprintln(cmd.OutOrStdout(), v.String())
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+
+				return enc.Encode(v)
+			default:
+				return fmt.Errorf("unknown format %q, must be text or json", format)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "o", "text", "output format, text/json")
+
+	return cmd
+}
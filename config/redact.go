@@ -0,0 +1,36 @@
+package config
+
+import "reflect"
+
+const redacted = "******"
+
+// Redacted 返回配置的副本，带有 `redact:"true"` 标签的敏感字段会被替换，用于打印和日志.
+func (c *Config) Redacted() *Config {
+	cp := *c
+	redact(reflect.ValueOf(&cp).Elem())
+
+	return &cp
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			redact(field)
+		case field.Kind() == reflect.String && t.Field(i).Tag.Get("redact") == "true" && field.String() != "":
+			field.SetString(redacted)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String &&
+			t.Field(i).Tag.Get("redact") == "true" && field.Len() > 0:
+			// 副本与原配置共享底层数组，需要替换为新的切片
+			values := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			for j := 0; j < field.Len(); j++ {
+				values.Index(j).SetString(redacted)
+			}
+
+			field.Set(values)
+		}
+	}
+}
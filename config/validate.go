@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var errInvalidConfig = errors.New("invalid config")
+
+// Validate 检查 cleanenv 无法覆盖的配置约束.
+func (c *Config) Validate() error {
+	var errs []string
+
+	if _, err := strconv.ParseUint(c.HTTP.Port, 10, 16); err != nil {
+		errs = append(errs, fmt.Sprintf("http.port %q is not a valid port", c.HTTP.Port))
+	}
+
+	if c.HTTP.ShutdownDelay < 0 {
+		errs = append(errs, "http.shutdownDelay must not be negative")
+	}
+
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Sprintf("http.trustedProxies %q is not a valid IP or CIDR", proxy))
+		}
+	}
+
+	switch c.Log.Level {
+	case "trace", "debug", "info", "warn", "warning", "error", "fatal", "panic":
+	default:
+		errs = append(errs, fmt.Sprintf("log.level %q is unknown", c.Log.Level))
+	}
+
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Sprintf("log.format %q must be text or json", c.Log.Format))
+	}
+
+	switch c.Database.Driver {
+	case "mysql":
+		errs = append(errs, c.MySQL.validate()...)
+	case "sqlite":
+		if c.SQLite.Path == "" {
+			errs = append(errs, "sqlite.path is required by sqlite driver")
+		}
+	case "postgres":
+		errs = append(errs, c.Postgres.validate()...)
+	default:
+		errs = append(errs, fmt.Sprintf("database.driver %q must be mysql, postgres or sqlite", c.Database.Driver))
+	}
+
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		errs = append(errs, "auth token ttl must be positive")
+	}
+
+	if c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+		errs = append(errs, "auth.refreshTokenTtl must not be shorter than auth.accessTokenTtl")
+	}
+
+	// redis 和 tiered 依赖 Redis，未配置 Redis 时只能使用进程内缓存
+	if c.Redis.URL == "" && c.Cache.Backend != "memory" {
+		errs = append(errs, fmt.Sprintf("cache.backend %q requires redis.url, use memory without redis", c.Cache.Backend))
+	}
+
+	switch c.Cache.Backend {
+	case "redis":
+	case "memory", "tiered":
+		if c.Cache.Size <= 0 || c.Cache.LocalTTL <= 0 {
+			errs = append(errs, "cache.size and cache.localTtl must be positive")
+		}
+
+		if c.Cache.Backend == "tiered" && c.Cache.Channel == "" {
+			errs = append(errs, "cache.channel is required by tiered backend")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("cache.backend %q must be memory, redis or tiered", c.Cache.Backend))
+	}
+
+	switch c.Tracing.Exporter {
+	case "", "none", "otlp", "stdout":
+	case "file":
+		if c.Tracing.File == "" {
+			errs = append(errs, "tracing.file is required by file exporter")
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("tracing.exporter %q must be none, otlp, stdout or file", c.Tracing.Exporter))
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, "tracing.sampleRatio must be between 0 and 1")
+	}
+
+	errs = append(errs, c.RateLimit.validate()...)
+
+	if c.Purge.UserRetention < 0 || (c.Purge.UserRetention > 0 && c.Purge.Interval <= 0) {
+		errs = append(errs, "purge.userRetention must not be negative and purge.interval must be positive")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", errInvalidConfig, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (r RateLimit) validate() []string {
+	var errs []string
+
+	for name, rule := range r.Rules {
+		switch rule.Algorithm {
+		case "", "sliding-window", "token-bucket":
+		default:
+			errs = append(errs, fmt.Sprintf("rateLimit.rules.%s.algorithm %q must be sliding-window or token-bucket", name, rule.Algorithm))
+		}
+
+		switch rule.Key {
+		case "", "ip", "user", "route":
+		default:
+			errs = append(errs, fmt.Sprintf("rateLimit.rules.%s.key %q must be ip, user or route", name, rule.Key))
+		}
+
+		if rule.Rate <= 0 || rule.Period <= 0 || rule.Burst < 0 {
+			errs = append(errs, fmt.Sprintf("rateLimit.rules.%s rate and period must be positive", name))
+		}
+	}
+
+	return errs
+}
+
+func (m MySQL) validate() []string {
+	var errs []string
+
+	if m.DSN == "" {
+		errs = append(errs, "mysql.dsn is required by mysql driver")
+	}
+
+	if m.MaxOpenConns <= 0 || m.MaxIdleConns < 0 || m.ConnMaxLifetime < 0 {
+		errs = append(errs, "mysql connection pool settings must be positive")
+	}
+
+	switch m.TxIsolation {
+	case "", "read-uncommitted", "read-committed", "repeatable-read", "serializable":
+	default:
+		errs = append(errs, fmt.Sprintf("mysql.txIsolation %q is unknown", m.TxIsolation))
+	}
+
+	if m.TxMaxRetries < 0 {
+		errs = append(errs, "mysql.txMaxRetries must not be negative")
+	}
+
+	if len(m.ReplicaDSNs) > 0 && m.ReplicaCheckInterval <= 0 {
+		errs = append(errs, "mysql.replicaCheckInterval must be positive when replicas are configured")
+	}
+
+	return errs
+}
+
+func (p Postgres) validate() []string {
+	var errs []string
+
+	if p.DSN == "" {
+		errs = append(errs, "postgres.dsn is required by postgres driver")
+	}
+
+	if p.MaxOpenConns <= 0 || p.MaxIdleConns < 0 || p.ConnMaxLifetime < 0 {
+		errs = append(errs, "postgres connection pool settings must be positive")
+	}
+
+	switch p.TxIsolation {
+	case "", "read-uncommitted", "read-committed", "repeatable-read", "serializable":
+	default:
+		errs = append(errs, fmt.Sprintf("postgres.txIsolation %q is unknown", p.TxIsolation))
+	}
+
+	if p.TxMaxRetries < 0 {
+		errs = append(errs, "postgres.txMaxRetries must not be negative")
+	}
+
+	return errs
+}
@@ -13,7 +13,7 @@ import (
 const (
 	// Attempts connection.
 	host       = "app:8080"
-	healthPath = "http://" + host + "/healthz"
+	healthPath = "http://" + host + "/readyz"
 	attempts   = 20
 
 	// HTTP REST.
@@ -61,12 +61,112 @@ func TestHTTPCreateUser(t *testing.T) {
 		"password": "pass@123",
 		"confirmPassword": "pass@123"
 	}`
+	Test(t,
+		Description("Create User Unauthorized"),
+		Post(basePath+"/users"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(body),
+		Expect().Status().Equal(http.StatusUnauthorized),
+	)
+
 	Test(t,
 		Description("Create User Success"),
 		Post(basePath+"/users"),
 		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("Authorization").Add("Bearer "+login(t)),
 		Send().Body().String(body),
 		Expect().Status().Equal(http.StatusOK),
 		Expect().Body().JSON().JQ(".username").Equal("user"),
 	)
 }
+
+// HTTP DELETE: /v1/users/:username, POST: /v1/users/:username/restore.
+func TestHTTPRestoreUser(t *testing.T) {
+	t.Parallel()
+
+	token := login(t)
+	body := `{
+		"username": "restore",
+		"email": "restore@example.com",
+		"password": "pass@123",
+		"confirmPassword": "pass@123"
+	}`
+	Test(t,
+		Description("Create User Success"),
+		Post(basePath+"/users"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Headers("Authorization").Add("Bearer "+token),
+		Send().Body().String(body),
+		Expect().Status().Equal(http.StatusOK),
+	)
+
+	Test(t,
+		Description("Delete User Success"),
+		Delete(basePath+"/users/restore"),
+		Send().Headers("Authorization").Add("Bearer "+token),
+		Expect().Status().Equal(http.StatusOK),
+	)
+
+	Test(t,
+		Description("Get Deleted User Not Found"),
+		Get(basePath+"/users/restore"),
+		Send().Headers("Authorization").Add("Bearer "+token),
+		Expect().Status().Equal(http.StatusNotFound),
+	)
+
+	Test(t,
+		Description("Restore User Success"),
+		Post(basePath+"/users/restore/restore"),
+		Send().Headers("Authorization").Add("Bearer "+token),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".username").Equal("restore"),
+	)
+}
+
+// HTTP POST: /v1/auth/login.
+func TestHTTPLogin(t *testing.T) {
+	t.Parallel()
+
+	body := `{
+		"username": "admin",
+		"password": "admin!123"
+	}`
+	Test(t,
+		Description("Login Success"),
+		Post(basePath+"/auth/login"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(body),
+		Expect().Status().Equal(http.StatusOK),
+		Expect().Body().JSON().JQ(".tokenType").Equal("Bearer"),
+	)
+
+	body = `{
+		"username": "admin",
+		"password": "wrong!123"
+	}`
+	Test(t,
+		Description("Login Wrong Password"),
+		Post(basePath+"/auth/login"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(body),
+		Expect().Status().Equal(http.StatusUnauthorized),
+	)
+}
+
+// 使用默认的超级用户登录，返回 Access Token.
+func login(t *testing.T) string {
+	t.Helper()
+
+	var accessToken string
+
+	Test(t,
+		Description("Login"),
+		Post(basePath+"/auth/login"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(`{"username": "admin", "password": "admin!123"}`),
+		Expect().Status().Equal(http.StatusOK),
+		Store().Response().Body().JSON().JQ(".accessToken").In(&accessToken),
+	)
+
+	return accessToken
+}
@@ -2,10 +2,12 @@
 package app
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/ninehills/go-webapp-template/internal/entity/validation"
 	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
 	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+	"github.com/ninehills/go-webapp-template/internal/service"
 	"github.com/ninehills/go-webapp-template/pkg/httpserver"
 )
 
@@ -26,23 +29,47 @@ func Run(cfgFile string) {
 		log.Fatalf("Config error: %s", err)
 	}
 
-	log.Printf("Config: %+v", cfg)
+	if err = cfg.Validate(); err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	log.Printf("Config: %+v", cfg.Redacted())
 
 	// 初始化全部依赖
 	dep := dependency.NewDependency(cfg)
 	defer dep.Close()
 
-	// 启动日志动态加载逻辑
-	go config.Watcher(cfgFile, dep.ReloadLogger)
+	// 启动日志、限流规则动态加载逻辑
+	go config.Watcher(cfgFile, dep.Reload)
 
 	l := dep.Logger
 
+	// 自动执行数据库迁移，需要在 NewRouter 初始化默认用户之前完成
+	if autoMigrate(cfg) {
+		l.Info("Auto migrate database...")
+
+		m, err := newMigrator(dep)
+		if err != nil {
+			log.Fatalf("app - Run - newMigrator: %s", err)
+		}
+
+		if err = m.Up(context.Background()); err != nil {
+			log.Fatalf("app - Run - migrate up: %s", err)
+		}
+	}
+
 	// 初始化 Gin
 	if !cfg.App.Debug {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	handler := gin.New()
+	// 使用 *gin.Context 作为 context.Context 时回退到 c.Request.Context()，以便获取 trace span
+	handler.ContextWithFallback = true
+	// 只信任配置的反向代理设置的 X-Forwarded-For，否则客户端可以伪造 IP 绕过按 ip 限流
+	if err = handler.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		log.Fatalf("app - Run - SetTrustedProxies: %s", err)
+	}
 
 	// 绑定自定义的 Validator 参数校验器
 	validation.BindValidator()
@@ -50,9 +77,21 @@ func Run(cfgFile string) {
 	// 初始化中间件
 	middleware.RegisterGlobalMiddleware(handler)
 
+	// 创建所有 Service
+	svcs := service.NewServices(dep)
+
 	// 初始化 router
 	l.Info("Controller router init...")
-	http.NewRouter(handler, dep)
+	http.NewRouter(handler, dep, svcs)
+
+	// 定期永久删除超过保留期的软删除用户
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.Purge.UserRetention > 0 {
+		go runUserPurge(ctx, l, dep.Locker, svcs.User,
+			time.Duration(cfg.Purge.UserRetention)*time.Second, time.Duration(cfg.Purge.Interval)*time.Second)
+	}
 	l.Infof("Start http server at %s", cfg.HTTP.Port)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
@@ -66,6 +105,15 @@ func Run(cfgFile string) {
 	case err = <-httpServer.Notify():
 		l.Errorf("app - Run - httpServer.Notify: %w", err)
 	}
+
+	// 先让 /readyz 失败，等待负载均衡摘除流量
+	dep.Health.Shutdown()
+
+	if delay := time.Duration(cfg.HTTP.ShutdownDelay) * time.Second; delay > 0 {
+		l.Infof("app - Run - waiting %s before shutdown", delay)
+		time.Sleep(delay)
+	}
+
 	// Shutdown
 	err = httpServer.Shutdown()
 	if err != nil {
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ninehills/go-webapp-template/config"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/pkg/migrate"
+	"github.com/ninehills/go-webapp-template/sql/migrations"
+	pgmigrations "github.com/ninehills/go-webapp-template/sql/postgres/migrations"
+	sqlitemigrations "github.com/ninehills/go-webapp-template/sql/sqlite/migrations"
+)
+
+var errMigrateUsage = errors.New("usage: migrate up|down|status|goto <version>")
+
+// Migrate 执行数据库迁移子命令.
+func Migrate(cfgFile string, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	cfg, err := config.LoadConfig(cfgFile)
+	if err != nil {
+		return fmt.Errorf("app - Migrate - load config: %w", err)
+	}
+
+	dep := dependency.NewDependency(cfg)
+	defer dep.Close()
+
+	m, err := newMigrator(dep)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx)
+	case "goto":
+		if len(args) != 2 {
+			return errMigrateUsage
+		}
+
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("app - Migrate - invalid version %s: %w", args[1], err)
+		}
+
+		return m.Goto(ctx, version)
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		printMigrateStatus(status)
+
+		return nil
+	default:
+		return errMigrateUsage
+	}
+}
+
+// 根据数据库类型选择迁移文件和方言.
+func newMigrator(dep *dependency.Dependency) (*migrate.Migrator, error) {
+	fsys, dialect := fs.FS(migrations.FS), migrate.DialectMySQL
+
+	switch {
+	case dep.SQLite != nil:
+		fsys, dialect = sqlitemigrations.FS, migrate.DialectSQLite
+	case dep.Postgres != nil:
+		fsys, dialect = pgmigrations.FS, migrate.DialectPostgres
+	}
+
+	m, err := migrate.New(dep.DB, dep.Logger, fsys, migrate.Dialect(dialect))
+	if err != nil {
+		return nil, fmt.Errorf("app - newMigrator - migrate.New: %w", err)
+	}
+
+	return m, nil
+}
+
+func autoMigrate(cfg *config.Config) bool {
+	switch cfg.Database.Driver {
+	case "sqlite":
+		return cfg.SQLite.AutoMigrate
+	case "postgres":
+		return cfg.Postgres.AutoMigrate
+	}
+
+	return cfg.MySQL.AutoMigrate
+}
+
+func printMigrateStatus(status []migrate.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\tDIRTY\tAPPLIED AT")
+
+	for _, s := range status {
+		appliedAt := "-"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%t\t%t\t%s\n", s.Version, s.Name, s.Applied, s.Dirty, appliedAt)
+	}
+
+	w.Flush()
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/lock"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+const (
+	purgeUsersLock = "purge:users"
+	purgeLockTTL   = time.Minute
+)
+
+// 定期永久删除超过保留期的软删除用户，直到 ctx 结束.
+// 多个实例通过分布式锁保证同一时间只有一个实例执行，其他实例直接跳过本轮.
+func runUserPurge(
+	ctx context.Context, l logger.Logger, locker *lock.Locker, user service.User, retention, interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purgeUsers(ctx, l, locker, user, retention)
+		}
+	}
+}
+
+func purgeUsers(ctx context.Context, l logger.Logger, locker *lock.Locker, user service.User, retention time.Duration) {
+	lk, err := locker.TryAcquire(ctx, purgeUsersLock, purgeLockTTL)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		l.Debugf("app - purgeUsers - another instance is purging, skip")
+
+		return
+	} else if err != nil {
+		l.Errorf("app - purgeUsers - acquire lock: %v", err)
+
+		return
+	}
+
+	defer func() {
+		if err := lk.Release(context.Background()); err != nil {
+			l.Warnf("app - purgeUsers - release lock: %v", err)
+		}
+	}()
+
+	n, err := user.Purge(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		l.Errorf("app - purgeUsers - purge: %v", err)
+
+		return
+	}
+
+	if n > 0 {
+		l.Infof("app - purgeUsers - purged %d users deleted more than %s ago", n, retention)
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ninehills/go-webapp-template/apis/httpv1"
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+type authRoutes struct {
+	s service.Auth
+	l logger.Logger
+}
+
+// 注意：登录相关接口的请求体中包含密码和令牌，不要挂载审计中间件.
+func newAuthRoutes(handler *gin.RouterGroup, l logger.Logger, serv *service.Services, midd *middleware.Middlewares) {
+	r := &authRoutes{
+		l: l,
+		s: serv.Auth,
+	}
+	handler.POST("/auth/login",
+		midd.RateLimit.Limit("login"),
+		r.login)
+	handler.POST("/auth/refresh",
+		r.refresh)
+	handler.POST("/auth/logout",
+		r.logout)
+}
+
+// @Summary     Login
+// @Description Login with username and password, returns access token and refresh token
+// @ID          login
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body httpv1.LoginRequest true "Credentials"
+// @Success     200 {object} httpv1.LoginResponse
+// @Failure     400 {object} httpv1.ErrorResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     429 {object} httpv1.ErrorResponse
+// @Failure     500 {object} httpv1.ErrorResponse
+// @Router      /v1/auth/login [post].
+func (r *authRoutes) login(c *gin.Context) {
+	var request httpv1.LoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - login invalid request body")
+		exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	t, err := r.s.Login(c, request.Username, request.Password)
+	if err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - login failed")
+		exception.ResponseWithError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary     Refresh token
+// @Description Exchange a refresh token for a new token pair, the old refresh token is revoked
+// @ID          refresh-token
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body httpv1.RefreshRequest true "Refresh token"
+// @Success     200 {object} httpv1.RefreshResponse
+// @Failure     400 {object} httpv1.ErrorResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     500 {object} httpv1.ErrorResponse
+// @Router      /v1/auth/refresh [post].
+func (r *authRoutes) refresh(c *gin.Context) {
+	var request httpv1.RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - refresh invalid request body")
+		exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	t, err := r.s.Refresh(c, request.RefreshToken)
+	if err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - refresh failed")
+		exception.ResponseWithError(c, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary     Logout
+// @Description Revoke the refresh token
+// @ID          logout
+// @Tags  	    auth
+// @Accept      json
+// @Produce     json
+// @Param       request body httpv1.LogoutRequest true "Refresh token"
+// @Success     200 {object} httpv1.LogoutResponse
+// @Failure     400 {object} httpv1.ErrorResponse
+// @Failure     500 {object} httpv1.ErrorResponse
+// @Router      /v1/auth/logout [post].
+func (r *authRoutes) logout(c *gin.Context) {
+	var request httpv1.LogoutRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - logout invalid request body")
+		exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	if err := r.s.Logout(c, request.RefreshToken); err != nil {
+		r.l.Ctx(c).Err(err).Error("http - v1 - logout failed")
+		exception.ResponseWithError(c, err)
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
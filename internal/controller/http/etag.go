@@ -0,0 +1,41 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ninehills/go-webapp-template/internal/entity"
+)
+
+const weakETagPrefix = "W/"
+
+// 用户的 ETag，版本号在每次更新时加一.
+func userETag(u entity.User) string {
+	return `"` + strconv.FormatInt(u.Version, 10) + `"`
+}
+
+// 判断 If-Match / If-None-Match 请求头是否匹配 etag，header 可以是 "*" 或逗号分隔的 ETag 列表.
+// If-Match 使用强比较，弱 ETag 永远不匹配；If-None-Match 使用弱比较，忽略 W/ 前缀.
+// https://www.rfc-editor.org/rfc/rfc9110#section-8.8.3.2
+func etagMatch(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, weakETagPrefix) {
+			if !weak {
+				continue
+			}
+
+			tag = strings.TrimPrefix(tag, weakETagPrefix)
+		}
+
+		if tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header string
+		weak   bool
+		match  bool
+	}{
+		{header: `"3"`, match: true},
+		{header: `"2"`, match: false},
+		{header: `"1", "3"`, match: true},
+		{header: `*`, match: true},
+		{header: `W/"3"`, weak: false, match: false},
+		{header: `W/"3"`, weak: true, match: true},
+		{header: `3`, match: false},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.match, etagMatch(tc.header, `"3"`, tc.weak), tc.header)
+	}
+}
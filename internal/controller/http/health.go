@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ninehills/go-webapp-template/pkg/health"
+)
+
+type healthRoutes struct {
+	h *health.Checker
+}
+
+// K8s probe，均为公开接口.
+func newHealthRoutes(handler *gin.Engine, h *health.Checker) {
+	r := &healthRoutes{
+		h: h,
+	}
+	handler.GET("/livez", r.livez)
+	handler.GET("/readyz", r.readyz)
+	// 兼容旧的探针路径
+	handler.GET("/healthz", r.livez)
+}
+
+// @Summary     Liveness probe
+// @Description The process is alive, dependencies are not checked
+// @ID          livez
+// @Tags  	    health
+// @Produce     json
+// @Success     200 {object} health.Result
+// @Router      /livez [get].
+func (r *healthRoutes) livez(c *gin.Context) {
+	c.JSON(http.StatusOK, r.h.Live())
+}
+
+// @Summary     Readiness probe
+// @Description Check MySQL, Redis and shutdown state, the result is cached for a short time
+// @ID          readyz
+// @Tags  	    health
+// @Produce     json
+// @Success     200 {object} health.Result
+// @Failure     503 {object} health.Result
+// @Router      /readyz [get].
+func (r *healthRoutes) readyz(c *gin.Context) {
+	result := r.h.Ready()
+	if !result.OK() {
+		c.JSON(http.StatusServiceUnavailable, result)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"github.com/ninehills/go-webapp-template/apis/httpv1"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+)
+
+const (
+	// https://www.rfc-editor.org/rfc/rfc7396
+	mergePatchContentType = "application/merge-patch+json"
+	// https://www.rfc-editor.org/rfc/rfc6902
+	jsonPatchContentType = "application/json-patch+json"
+)
+
+var errNotObject = errors.New("merge patch must be a json object")
+
+// 解析 JSON Merge Patch，返回请求体以及值为 null 的字段.
+func decodeMergePatch(body []byte) (httpv1.PatchUserRequest, map[string]bool, error) {
+	var (
+		request httpv1.PatchUserRequest
+		fields  map[string]json.RawMessage
+	)
+
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return request, nil, errNotObject
+	}
+
+	nulls := make(map[string]bool)
+
+	for k, v := range fields {
+		if bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+			nulls[k] = true
+		}
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(&request); err != nil {
+		return request, nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return request, nulls, nil
+}
+
+// 将 JSON Patch 应用到当前用户的可修改字段上，再转换为等价的 JSON Merge Patch，
+// 之后与 merge patch 使用相同的校验和更新逻辑.
+func jsonPatchToMergePatch(cur entity.User, body []byte) ([]byte, error) {
+	patch, err := jsonpatch.DecodePatch(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json patch: %w", err)
+	}
+
+	original, err := json.Marshal(httpv1.PatchUserRequest{
+		Status:      &cur.Status,
+		Email:       &cur.Email,
+		Description: &cur.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal user failed: %w", err)
+	}
+
+	modified, err := patch.Apply(original)
+	if err != nil {
+		return nil, fmt.Errorf("apply json patch failed: %w", err)
+	}
+
+	merge, err := jsonpatch.CreateMergePatch(original, modified)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json patch result: %w", err)
+	}
+
+	return merge, nil
+}
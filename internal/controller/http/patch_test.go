@@ -0,0 +1,60 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/entity"
+)
+
+func TestDecodeMergePatch(t *testing.T) {
+	t.Parallel()
+
+	request, nulls, err := decodeMergePatch([]byte(`{"description":null,"status":2}`))
+	require.NoError(t, err)
+	require.True(t, nulls["description"])
+	require.Nil(t, request.Description)
+	require.Nil(t, request.Email)
+	require.Equal(t, int32(2), *request.Status)
+
+	// 空字符串与缺省区分
+	request, nulls, err = decodeMergePatch([]byte(`{"description":""}`))
+	require.NoError(t, err)
+	require.Empty(t, nulls)
+	require.NotNil(t, request.Description)
+	require.Empty(t, *request.Description)
+	require.NoError(t, binding.Validator.ValidateStruct(&request))
+
+	_, _, err = decodeMergePatch([]byte(`[]`))
+	require.Error(t, err)
+
+	_, _, err = decodeMergePatch([]byte(`{"username":"bob"}`))
+	require.Error(t, err)
+
+	// email 不能为空字符串
+	request, _, err = decodeMergePatch([]byte(`{"email":""}`))
+	require.NoError(t, err)
+	require.Error(t, binding.Validator.ValidateStruct(&request))
+}
+
+func TestJSONPatchToMergePatch(t *testing.T) {
+	t.Parallel()
+
+	cur := entity.User{Username: "alice", Status: 1, Email: "alice@example.com", Description: "hello", Version: 3}
+
+	merge, err := jsonPatchToMergePatch(cur, []byte(`[
+		{"op":"remove","path":"/description"},
+		{"op":"replace","path":"/status","value":2}
+	]`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"description":null,"status":2}`, string(merge))
+
+	// test 操作失败时不修改
+	_, err = jsonPatchToMergePatch(cur, []byte(`[{"op":"test","path":"/email","value":"bob@example.com"}]`))
+	require.Error(t, err)
+
+	_, err = jsonPatchToMergePatch(cur, []byte(`{"op":"remove"}`))
+	require.Error(t, err)
+}
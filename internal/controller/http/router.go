@@ -4,17 +4,25 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"github.com/ninehills/go-webapp-template/internal/dao"
 	"github.com/ninehills/go-webapp-template/internal/entity"
 	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
 	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
 	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/lock"
+)
+
+const (
+	initDefaultUserLock = "init:default-user"
+	initLockTTL         = 30 * time.Second
+	initLockWait        = time.Minute
 )
 
 // NewRouter -.
@@ -23,8 +31,12 @@ import (
 // @description GO WEBAPP TEMPLATE API
 // @version     1.0
 // @host        localhost:8080
-// @BasePath    /.
-func NewRouter(handler *gin.Engine, deps *dependency.Dependency) {
+// @BasePath    /
+// @securityDefinitions.apikey BearerAuth
+// @in          header
+// @name        Authorization
+// @description Type "Bearer" followed by a space and the access token.
+func NewRouter(handler *gin.Engine, deps *dependency.Dependency, svcs *service.Services) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
@@ -33,20 +45,20 @@ func NewRouter(handler *gin.Engine, deps *dependency.Dependency) {
 	swaggerHandler := ginSwagger.DisablingWrapHandler(swaggerFiles.Handler, "DISABLE_SWAGGER_HTTP_HANDLER")
 	handler.GET("/swagger/*any", swaggerHandler)
 
+	// 以下 swagger / probe / metrics 均为公开接口
+
 	// K8s probe
-	handler.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	newHealthRoutes(handler, deps.Health)
 
 	// Prometheus metrics
 	handler.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// 创建所有 Service
-	svcs := service.NewServices(deps)
-
 	// 创建非全局的 middleware
-	middlewares := middleware.NewMiddlewares(deps)
+	middlewares := middleware.NewMiddlewares(deps, svcs)
 
-	// Init default user
-	InitDefaultUser(svcs.User, deps.Config.App.SuperUser, deps.Config.App.SuperPassword)
+	// Init default roles and user
+	InitDefaultRoles(svcs.Authz)
+	InitDefaultUser(svcs.User, svcs.Authz, deps.Locker, deps.Config.App.SuperUser, deps.Config.App.SuperPassword)
 
 	l := deps.Logger
 
@@ -55,16 +67,50 @@ func NewRouter(handler *gin.Engine, deps *dependency.Dependency) {
 	// v1 API
 	v1 := handler.Group("/v1")
 	{
-		newUserRoutes(v1, l, svcs, middlewares)
+		// 公开接口，无需登录
+		newAuthRoutes(v1, l, svcs, middlewares)
+
+		// 需要登录的接口
+		authorized := v1.Group("", middlewares.Auth.RequireSession())
+		newUserRoutes(authorized, l, svcs, middlewares)
+	}
+}
+
+func InitDefaultRoles(authz service.Authz) {
+	for _, role := range entity.DefaultRoles() {
+		_, err := authz.EnsureRole(context.Background(), role)
+		if err != nil {
+			log.Printf("Init default role %s failed: %s", role.Name, err)
+			panic(err)
+		}
 	}
 }
 
-func InitDefaultUser(user service.User, username, password string) {
-	_, err := user.Get(context.Background(), username)
+// 多个实例同时启动时，通过分布式锁保证只有一个实例创建默认用户.
+func InitDefaultUser(user service.User, authz service.Authz, locker *lock.Locker, username, password string) {
+	ctx, cancel := context.WithTimeout(context.Background(), initLockWait)
+	defer cancel()
+
+	l, err := locker.Acquire(ctx, initDefaultUserLock, initLockTTL)
+	if err != nil {
+		log.Printf("Init default user acquire lock failed: %s", err)
+		panic(err)
+	}
+
+	defer func() {
+		if err := l.Release(context.Background()); err != nil {
+			log.Printf("Init default user release lock failed: %s", err)
+		}
+	}()
+
+	// 创建用户之后立即读取并分配角色，需要读取主库
+	ctx = dao.WithPrimary(ctx)
+
+	_, err = user.Get(ctx, username)
 	if err != nil {
 		log.Printf("Init default user %s", username)
 
-		_, err := user.Create(context.Background(), entity.User{
+		_, err := user.Create(ctx, entity.User{
 			Username:    username,
 			Password:    password,
 			Email:       fmt.Sprintf("%s@example.com", username),
@@ -76,4 +122,11 @@ func InitDefaultUser(user service.User, username, password string) {
 			panic(err)
 		}
 	}
+
+	// 对已经存在的超级用户同样补充管理员角色
+	err = authz.AssignRole(ctx, username, entity.RoleAdmin)
+	if err != nil {
+		log.Printf("Init default user role failed: %s", err)
+		panic(err)
+	}
 }
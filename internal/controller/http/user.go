@@ -1,11 +1,15 @@
 package http
 
 import (
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
 	"github.com/ninehills/go-webapp-template/apis/httpv1"
+	"github.com/ninehills/go-webapp-template/internal/dao"
 	"github.com/ninehills/go-webapp-template/internal/entity"
 	"github.com/ninehills/go-webapp-template/internal/infra/exception"
 	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
@@ -16,6 +20,7 @@ import (
 
 type userRoutes struct {
 	s service.User
+	a service.Authz
 	l logger.Logger
 }
 
@@ -23,20 +28,40 @@ func newUserRoutes(handler *gin.RouterGroup, l logger.Logger, serv *service.Serv
 	r := &userRoutes{
 		l: l,
 		s: serv.User,
+		a: serv.Authz,
 	}
 	handler.POST("/users",
+		midd.Authz.Require(entity.PermUserCreate),
 		midd.Audit.Audit(),
+		midd.Idempotency.Idempotent(),
 		r.createUser)
 	handler.GET("/users",
+		midd.RateLimit.Limit("listUsers"),
+		midd.Authz.Require(entity.PermUserList),
 		r.ListUsers)
 	handler.GET("/users/:username",
+		midd.Authz.RequireOn(entity.PermUserRead, "username"),
 		r.getUser)
 	handler.PUT("/users/:username",
+		midd.Authz.RequireOn(entity.PermUserUpdate, "username"),
 		midd.Audit.Audit(),
+		midd.Idempotency.Idempotent(),
 		r.updateUser)
+	handler.PATCH("/users/:username",
+		midd.Authz.RequireOn(entity.PermUserUpdate, "username"),
+		midd.Audit.Audit(),
+		midd.Idempotency.Idempotent(),
+		r.patchUser)
 	handler.DELETE("/users/:username",
+		midd.Authz.RequireOn(entity.PermUserDelete, "username"),
 		midd.Audit.Audit(),
+		midd.Idempotency.Idempotent(),
 		r.deleteUser)
+	handler.POST("/users/:username/restore",
+		midd.Authz.Require(entity.PermUserRestore),
+		midd.Audit.Audit(),
+		midd.Idempotency.Idempotent(),
+		r.restoreUser)
 }
 
 // @Summary     Get user
@@ -45,7 +70,13 @@ func newUserRoutes(handler *gin.RouterGroup, l logger.Logger, serv *service.Serv
 // @Tags  	    user
 // @Param 		username path string true "Username"
 // @Produce     json
+// @Security    BearerAuth
+// @Param       If-None-Match header string false "ETag of the cached user, returns 304 if not modified"
 // @Success     200 {object} httpv1.GetUserResponse
+// @Header      200 {string} ETag "User version"
+// @Success     304 "Not Modified"
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
 // @Failure     500 {object} httpv1.ErrorResponse
 // @Router      /v1/users/:username [get].
 func (r *userRoutes) getUser(c *gin.Context) {
@@ -57,6 +88,15 @@ func (r *userRoutes) getUser(c *gin.Context) {
 		return
 	}
 
+	etag := userETag(user)
+	c.Header("ETag", etag)
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatch(inm, etag, true) {
+		c.Status(http.StatusNotModified)
+
+		return
+	}
+
 	c.JSON(http.StatusOK, user)
 }
 
@@ -66,7 +106,16 @@ func (r *userRoutes) getUser(c *gin.Context) {
 // @Tags  	    user
 // @Param 		username path string true "username"
 // @Produce     json
+// @Security    BearerAuth
+// @Param       Idempotency-Key header string false "Retries with the same key return the first response"
+// @Param       If-Match header string false "ETag from getUser, returns 412 if the user has been modified"
 // @Success     200 {object} httpv1.UpdateUserResponse
+// @Header      200 {string} ETag "User version"
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     409 {object} httpv1.ErrorResponse
+// @Failure     412 {object} httpv1.ErrorResponse
+// @Failure     422 {object} httpv1.ErrorResponse
 // @Failure     500 {object} httpv1.ErrorResponse
 // @Router      /v1/users/:username [PUT].
 func (r *userRoutes) updateUser(c *gin.Context) {
@@ -81,6 +130,10 @@ func (r *userRoutes) updateUser(c *gin.Context) {
 		return
 	}
 
+	if request.Status != 0 && !r.canUpdateStatus(c) {
+		return
+	}
+
 	// 当请求中密码不为空时，才会更新密码
 	if request.Password != "" || request.ConfirmPassword != "" {
 		err := password.ValidatePassword(request.Password, request.ConfirmPassword)
@@ -92,6 +145,18 @@ func (r *userRoutes) updateUser(c *gin.Context) {
 		}
 	}
 
+	// 带有 If-Match 时，只有当前版本匹配才更新，Update 会以该版本为条件防止检查之后的并发修改
+	var version int64
+
+	if c.GetHeader("If-Match") != "" {
+		cur, ok := r.current(c, username)
+		if !ok {
+			return
+		}
+
+		version = cur.Version
+	}
+
 	user, err := r.s.Update(
 		c, entity.User{
 			Username:    username,
@@ -99,6 +164,7 @@ func (r *userRoutes) updateUser(c *gin.Context) {
 			Email:       request.Email,
 			Description: request.Description,
 			Password:    request.Password,
+			Version:     version,
 		},
 	)
 	if err != nil {
@@ -108,9 +174,179 @@ func (r *userRoutes) updateUser(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", userETag(user))
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary     Patch user
+// @Description Partially update user by username with JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902).
+// @Description Absent fields are not modified, description can be cleared with null or empty string.
+// @ID          patch-user
+// @Tags  	    user
+// @Param 		username path string true "username"
+// @Accept      application/merge-patch+json,application/json-patch+json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body httpv1.PatchUserRequest true "Merge patch, or an array of JSON Patch operations"
+// @Param       Idempotency-Key header string false "Retries with the same key return the first response"
+// @Param       If-Match header string false "ETag from getUser, returns 412 if the user has been modified"
+// @Success     200 {object} httpv1.PatchUserResponse
+// @Header      200 {string} ETag "User version"
+// @Failure     400 {object} httpv1.ErrorResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     404 {object} httpv1.ErrorResponse
+// @Failure     409 {object} httpv1.ErrorResponse
+// @Failure     412 {object} httpv1.ErrorResponse
+// @Failure     415 {object} httpv1.ErrorResponse
+// @Failure     422 {object} httpv1.ErrorResponse
+// @Failure     500 {object} httpv1.ErrorResponse
+// @Router      /v1/users/:username [PATCH].
+func (r *userRoutes) patchUser(c *gin.Context) {
+	username := c.Param("username")
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != mergePatchContentType && contentType != jsonPatchContentType {
+		exception.CodeResponse(c, http.StatusUnsupportedMediaType,
+			fmt.Sprintf("content type must be %s or %s", mergePatchContentType, jsonPatchContentType))
+
+		return
+	}
+
+	// patch 基于当前版本计算，更新时以该版本为条件
+	cur, ok := r.current(c, username)
+	if !ok {
+		return
+	}
+
+	if contentType == jsonPatchContentType {
+		body, err = jsonPatchToMergePatch(cur, body)
+		if err != nil {
+			r.l.Ctx(c).Err(err).Warn("http - v1 - patchUser invalid json patch")
+			exception.CodeResponse(c, http.StatusBadRequest, err.Error())
+
+			return
+		}
+	}
+
+	request, nulls, err := decodeMergePatch(body)
+	if err == nil {
+		err = binding.Validator.ValidateStruct(&request)
+	}
+
+	if err != nil {
+		r.l.Ctx(c).Err(err).Warn("http - v1 - patchUser invalid request body")
+		exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	for _, field := range []string{"status", "email", "password", "confirmPassword"} {
+		if nulls[field] {
+			exception.CodeResponse(c, http.StatusBadRequest, field+" can not be null")
+
+			return
+		}
+	}
+
+	if request.Status != nil && !r.canUpdateStatus(c) {
+		return
+	}
+
+	if request.Password != nil || request.ConfirmPassword != nil {
+		err := password.ValidatePassword(stringValue(request.Password), stringValue(request.ConfirmPassword))
+		if err != nil {
+			r.l.Ctx(c).Err(err).Warn("http - v1 - patchUser password is invalid")
+			exception.CodeResponse(c, http.StatusBadRequest, err.Error())
+
+			return
+		}
+	}
+
+	patch := entity.UserPatch{
+		Username:    username,
+		Status:      request.Status,
+		Email:       request.Email,
+		Description: request.Description,
+		Password:    request.Password,
+		Version:     cur.Version,
+	}
+
+	// null 代表清空
+	if nulls["description"] {
+		patch.Description = new(string)
+	}
+
+	user, err := r.s.Patch(c, patch)
+	if err != nil {
+		r.l.Ctx(c).Err(err).Error("http - v1 - patchUser failed")
+		exception.ResponseWithError(c, err)
+
+		return
+	}
+
+	c.Header("ETag", userETag(user))
 	c.JSON(http.StatusOK, user)
 }
 
+// 修改用户状态需要完整的 user:update 权限，仅能修改自己的用户不允许启用/禁用自己.
+// 没有权限时写入错误响应并返回 false.
+func (r *userRoutes) canUpdateStatus(c *gin.Context) bool {
+	p, _ := middleware.GetPrincipal(c)
+
+	allowed, err := r.a.Can(c, p, entity.PermUserUpdate, "")
+	if err != nil {
+		r.l.Ctx(c).Err(err).Error("http - v1 - check update status permission failed")
+		exception.ResponseWithError(c, err)
+
+		return false
+	}
+
+	if !allowed {
+		exception.CodeResponse(c, http.StatusForbidden, "permission denied: update user status")
+
+		return false
+	}
+
+	return true
+}
+
+// 获取当前用户，带有 If-Match 时要求与当前版本匹配，失败时写入错误响应并返回 false.
+// 随后的更新以该版本为条件，需要读取主库，避免只读副本的复制延迟导致版本落后.
+func (r *userRoutes) current(c *gin.Context, username string) (entity.User, bool) {
+	cur, err := r.s.Get(dao.WithPrimary(c), username)
+	if err != nil {
+		r.l.Ctx(c).Err(err).Error("http - v1 - get current user failed")
+		exception.ResponseWithError(c, err)
+
+		return entity.User{}, false
+	}
+
+	if im := c.GetHeader("If-Match"); im != "" && !etagMatch(im, userETag(cur), false) {
+		exception.ResponseWithError(c, exception.PreconditionFailed(
+			fmt.Errorf("user %s has been modified, current etag is %s", username, userETag(cur))))
+
+		return entity.User{}, false
+	}
+
+	return cur, true
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
 // @Summary     List users
 // @Description List user with pages
 // @ID          list-users
@@ -121,8 +357,13 @@ func (r *userRoutes) updateUser(c *gin.Context) {
 // @Param		orderBy		query	string	true	"Order by create_time"
 // @Param		username	query	string	true	"Username"
 // @Param		status		query	int32	true	"Status 1/2"
+// @Param		includeDeleted	query	bool	false	"Include deleted users, requires user:list-deleted permission"
 // @Produce     json
+// @Security    BearerAuth
 // @Success     200 {object} httpv1.ListUserResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     429 {object} httpv1.ErrorResponse
 // @Failure     500 {object} httpv1.ErrorResponse
 // @Router      /v1/users [get].
 func (r *userRoutes) ListUsers(c *gin.Context) {
@@ -136,6 +377,25 @@ func (r *userRoutes) ListUsers(c *gin.Context) {
 		return
 	}
 
+	// 查询已删除的用户需要单独的权限
+	if request.IncludeDeleted {
+		p, _ := middleware.GetPrincipal(c)
+
+		allowed, err := r.a.Can(c, p, entity.PermUserListDeleted, "")
+		if err != nil {
+			r.l.Ctx(c).Err(err).Error("http - v1 - ListUsers check permission failed")
+			exception.ResponseWithError(c, err)
+
+			return
+		}
+
+		if !allowed {
+			exception.CodeResponse(c, http.StatusForbidden, "permission denied: list deleted users")
+
+			return
+		}
+	}
+
 	pageresult, users, sErr := r.s.Query(
 		c, entity.PageQuery{
 			PageNo:   request.PageNo,
@@ -147,6 +407,8 @@ func (r *userRoutes) ListUsers(c *gin.Context) {
 			Username: request.Username,
 			Status:   request.Status,
 			Email:    request.Email,
+
+			IncludeDeleted: request.IncludeDeleted,
 		},
 	)
 	if sErr != nil {
@@ -170,9 +432,15 @@ func (r *userRoutes) ListUsers(c *gin.Context) {
 // @Tags  	    user
 // @Accept      json
 // @Produce     json
+// @Security    BearerAuth
 // @Param       request body httpv1.CreateUserRequest true "Set up user"
+// @Param       Idempotency-Key header string false "Retries with the same key return the first response"
 // @Success     200 {object} httpv1.CreateUserResponse
 // @Failure     400 {object} httpv1.ErrorResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     409 {object} httpv1.ErrorResponse
+// @Failure     422 {object} httpv1.ErrorResponse
 // @Failure     500 {object} httpv1.ErrorResponse
 // @Router      /v1/users [post].
 func (r *userRoutes) createUser(c *gin.Context) {
@@ -212,12 +480,18 @@ func (r *userRoutes) createUser(c *gin.Context) {
 }
 
 // @Summary     Delete user
-// @Description Delete user by username
+// @Description Soft delete user by username, the user can be restored until purged after the retention
 // @ID          delete-user
 // @Tags  	    user
 // @Param 		username path string true "Username"
 // @Produce     json
+// @Security    BearerAuth
+// @Param       Idempotency-Key header string false "Retries with the same key return the first response"
 // @Success     200 {object} httpv1.DeleteUserResponse
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     409 {object} httpv1.ErrorResponse
+// @Failure     422 {object} httpv1.ErrorResponse
 // @Failure     500 {object} httpv1.ErrorResponse
 // @Router      /v1/users/:username [DELETE].
 func (r *userRoutes) deleteUser(c *gin.Context) {
@@ -232,3 +506,33 @@ func (r *userRoutes) deleteUser(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// @Summary     Restore user
+// @Description Restore a soft deleted user by username
+// @ID          restore-user
+// @Tags  	    user
+// @Param 		username path string true "Username"
+// @Produce     json
+// @Security    BearerAuth
+// @Param       Idempotency-Key header string false "Retries with the same key return the first response"
+// @Success     200 {object} httpv1.RestoreUserResponse
+// @Header      200 {string} ETag "User version"
+// @Failure     401 {object} httpv1.ErrorResponse
+// @Failure     403 {object} httpv1.ErrorResponse
+// @Failure     404 {object} httpv1.ErrorResponse
+// @Failure     500 {object} httpv1.ErrorResponse
+// @Router      /v1/users/:username/restore [post].
+func (r *userRoutes) restoreUser(c *gin.Context) {
+	username := c.Param("username")
+
+	user, err := r.s.Restore(c, username)
+	if err != nil {
+		r.l.Ctx(c).Err(err).Error("http - v1 - restoreUser failed")
+		exception.ResponseWithError(c, err)
+
+		return
+	}
+
+	c.Header("ETag", userETag(user))
+	c.JSON(http.StatusOK, user)
+}
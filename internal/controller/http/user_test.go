@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+// fakeUser 记录调用参数，未实现的方法调用时 panic.
+type fakeUser struct {
+	service.User
+
+	primary  bool
+	restored string
+}
+
+func (u *fakeUser) Get(ctx context.Context, username string) (entity.User, error) {
+	u.primary = dao.UsePrimary(ctx)
+
+	return entity.User{Username: username, Version: 3}, nil
+}
+
+func (u *fakeUser) Restore(_ context.Context, username string) (entity.User, error) {
+	u.restored = username
+
+	return entity.User{Username: username, Version: 4}, nil
+}
+
+func TestCurrentReadsPrimary(t *testing.T) {
+	t.Parallel()
+
+	s := &fakeUser{}
+	r := &userRoutes{s: s, l: logger.New(logger.Config{Level: "error"})}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPut, "/v1/users/user1", nil)
+	c.Request.Header.Set("If-Match", `"3"`)
+
+	cur, ok := r.current(c, "user1")
+	require.True(t, ok)
+	require.Equal(t, int64(3), cur.Version)
+	require.True(t, s.primary)
+}
+
+func TestRestoreUserRoute(t *testing.T) {
+	t.Parallel()
+
+	s := &fakeUser{}
+	r := &userRoutes{s: s, l: logger.New(logger.Config{Level: "error"})}
+
+	handler := gin.New()
+	handler.POST("/v1/users/:username/restore", r.restoreUser)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/users/user1/restore", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, `"4"`, w.Header().Get("ETag"))
+	require.Equal(t, "user1", s.restored)
+}
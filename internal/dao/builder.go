@@ -0,0 +1,440 @@
+package dao
+
+// 人工编写的 SQL 构建器，所有的值都通过占位符传递，列名只允许来自代码中的常量或白名单.
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+var (
+	ErrInvalidIdentifier = errors.New("invalid sql identifier")
+	ErrUnsortable        = errors.New("column is not sortable")
+	ErrInvalidOrder      = errors.New("order must be asc or desc")
+	ErrEmptyIn           = errors.New("IN requires at least one value")
+)
+
+//nolint:gochecknoglobals
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Dialect 决定标识符的引用方式和占位符的格式.
+type Dialect int
+
+const (
+	// MySQL 和 SQLite：标识符不引用，占位符为 ?
+	DialectMySQL Dialect = iota
+	// PostgreSQL：user 是保留字，标识符使用双引号引用，占位符为 $1, $2, ...
+	DialectPostgres
+)
+
+// 按方言渲染 SQL 的 strings.Builder.
+type writer struct {
+	strings.Builder
+	dialect Dialect
+}
+
+// 写入标识符，调用前需要使用 identifierRegexp 校验.
+func (w *writer) ident(name string) {
+	if w.dialect != DialectPostgres {
+		w.WriteString(name)
+
+		return
+	}
+
+	for i, part := range strings.Split(name, ".") {
+		if i > 0 {
+			w.WriteString(".")
+		}
+
+		w.WriteString(`"` + part + `"`)
+	}
+}
+
+// 写入第 n 个（从 1 开始）参数的占位符.
+func (w *writer) placeholder(n int) {
+	if w.dialect == DialectPostgres {
+		w.WriteString("$" + strconv.Itoa(n))
+	} else {
+		w.WriteString("?")
+	}
+}
+
+// Cond 是 WHERE 中的一个条件，渲染为带占位符的 SQL 片段和对应的参数.
+type Cond interface {
+	build(w *writer, args []interface{}) ([]interface{}, error)
+}
+
+type compare struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+func (c compare) build(w *writer, args []interface{}) ([]interface{}, error) {
+	if !identifierRegexp.MatchString(c.column) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, c.column)
+	}
+
+	w.ident(c.column)
+	w.WriteString(" ")
+	w.WriteString(c.op)
+	w.WriteString(" ")
+	w.placeholder(len(args) + 1)
+
+	return append(args, c.value), nil
+}
+
+// Eq column = value.
+func Eq(column string, value interface{}) Cond { return compare{column, "=", value} }
+
+// Ne column <> value.
+func Ne(column string, value interface{}) Cond { return compare{column, "<>", value} }
+
+// Gt column > value.
+func Gt(column string, value interface{}) Cond { return compare{column, ">", value} }
+
+// Gte column >= value.
+func Gte(column string, value interface{}) Cond { return compare{column, ">=", value} }
+
+// Lt column < value.
+func Lt(column string, value interface{}) Cond { return compare{column, "<", value} }
+
+// Lte column <= value.
+func Lte(column string, value interface{}) Cond { return compare{column, "<=", value} }
+
+type isNull struct {
+	column string
+	not    bool
+}
+
+// IsNull column IS NULL.
+func IsNull(column string) Cond { return isNull{column, false} }
+
+// IsNotNull column IS NOT NULL.
+func IsNotNull(column string) Cond { return isNull{column, true} }
+
+func (c isNull) build(w *writer, args []interface{}) ([]interface{}, error) {
+	if !identifierRegexp.MatchString(c.column) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, c.column)
+	}
+
+	w.ident(c.column)
+
+	if c.not {
+		w.WriteString(" IS NOT NULL")
+	} else {
+		w.WriteString(" IS NULL")
+	}
+
+	return args, nil
+}
+
+// Between from <= column <= to，任意一端为 nil 代表不限制.
+func Between(column string, from, to interface{}) Cond {
+	conds := []Cond{}
+	if from != nil {
+		conds = append(conds, Gte(column, from))
+	}
+
+	if to != nil {
+		conds = append(conds, Lte(column, to))
+	}
+
+	return And(conds...)
+}
+
+// LikePrefix column LIKE 'prefix%'，prefix 中的通配符会被转义.
+func LikePrefix(column, prefix string) Cond {
+	return like{column, escapeLike(prefix) + "%"}
+}
+
+type like struct {
+	column  string
+	pattern string
+}
+
+// 使用 ! 作为转义字符，避免反斜杠在不同数据库字符串字面量中的差异.
+func (c like) build(w *writer, args []interface{}) ([]interface{}, error) {
+	if !identifierRegexp.MatchString(c.column) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, c.column)
+	}
+
+	w.ident(c.column)
+	w.WriteString(" LIKE ")
+	w.placeholder(len(args) + 1)
+	w.WriteString(" ESCAPE '!'")
+
+	return append(args, c.pattern), nil
+}
+
+type in struct {
+	column string
+	values []interface{}
+}
+
+// In column IN (values...).
+func In(column string, values ...interface{}) Cond { return in{column, values} }
+
+func (c in) build(w *writer, args []interface{}) ([]interface{}, error) {
+	if !identifierRegexp.MatchString(c.column) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, c.column)
+	}
+
+	if len(c.values) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyIn, c.column)
+	}
+
+	w.ident(c.column)
+	w.WriteString(" IN (")
+
+	for i := range c.values {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+
+		w.placeholder(len(args) + i + 1)
+	}
+
+	w.WriteString(")")
+
+	return append(args, c.values...), nil
+}
+
+type junction struct {
+	op    string
+	conds []Cond
+}
+
+// And 连接多个条件，空条件会被忽略.
+func And(conds ...Cond) Cond { return junction{" AND ", conds} }
+
+// Or 连接多个条件，空条件会被忽略.
+func Or(conds ...Cond) Cond { return junction{" OR ", conds} }
+
+func (j junction) build(w *writer, args []interface{}) ([]interface{}, error) {
+	return j.buildWithParens(w, args, true)
+}
+
+func (j junction) buildWithParens(w *writer, args []interface{}, parens bool) ([]interface{}, error) {
+	conds := make([]Cond, 0, len(j.conds))
+
+	for _, c := range j.conds {
+		if c != nil && !isEmpty(c) {
+			conds = append(conds, c)
+		}
+	}
+
+	parens = parens && len(conds) > 1
+	if parens {
+		w.WriteString("(")
+	}
+
+	var err error
+
+	for i, c := range conds {
+		if i > 0 {
+			w.WriteString(j.op)
+		}
+
+		args, err = c.build(w, args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if parens {
+		w.WriteString(")")
+	}
+
+	return args, nil
+}
+
+func isEmpty(c Cond) bool {
+	j, ok := c.(junction)
+	if !ok {
+		return false
+	}
+
+	for _, sub := range j.conds {
+		if sub != nil && !isEmpty(sub) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`).Replace(s)
+}
+
+// SelectBuilder 构建单表的 SELECT 语句.
+type SelectBuilder struct {
+	dialect  Dialect
+	table    string
+	columns  []string
+	where    []Cond
+	sortable map[string]bool
+	orderBy  string
+	order    string
+	offset   int64
+	limit    int64
+}
+
+// Select 创建 SELECT 构建器，table 和 columns 必须是代码中的常量.
+func Select(table string, columns ...string) *SelectBuilder {
+	return &SelectBuilder{
+		table:   table,
+		columns: columns,
+	}
+}
+
+// Dialect 设置 SQL 方言，默认为 DialectMySQL.
+func (b *SelectBuilder) Dialect(d Dialect) *SelectBuilder {
+	b.dialect = d
+
+	return b
+}
+
+// Where 追加 AND 条件.
+func (b *SelectBuilder) Where(conds ...Cond) *SelectBuilder {
+	b.where = append(b.where, conds...)
+
+	return b
+}
+
+// Sortable 声明允许排序的列，OrderBy 只接受这些列.
+func (b *SelectBuilder) Sortable(columns ...string) *SelectBuilder {
+	if b.sortable == nil {
+		b.sortable = map[string]bool{}
+	}
+
+	for _, c := range columns {
+		b.sortable[c] = true
+	}
+
+	return b
+}
+
+// OrderBy 设置排序，column 必须在 Sortable 白名单中，order 只能为 asc/desc（忽略大小写）.
+func (b *SelectBuilder) OrderBy(column, order string) *SelectBuilder {
+	b.orderBy = column
+	b.order = order
+
+	return b
+}
+
+// Page 设置分页，limit <= 0 代表不分页.
+func (b *SelectBuilder) Page(offset, limit int64) *SelectBuilder {
+	b.offset = offset
+	b.limit = limit
+
+	return b
+}
+
+// Build 生成查询语句.
+func (b *SelectBuilder) Build() (string, []interface{}, error) {
+	for _, c := range b.columns {
+		if c != "*" && !identifierRegexp.MatchString(c) {
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, c)
+		}
+	}
+
+	w, args, err := b.buildFrom(func(w *writer) {
+		w.WriteString("SELECT ")
+
+		if len(b.columns) == 0 {
+			w.WriteString("*")
+		}
+
+		for i, c := range b.columns {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+
+			if c == "*" {
+				w.WriteString(c)
+			} else {
+				w.ident(c)
+			}
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if b.orderBy != "" {
+		if !b.sortable[b.orderBy] || !identifierRegexp.MatchString(b.orderBy) {
+			return "", nil, fmt.Errorf("%w: %q", ErrUnsortable, b.orderBy)
+		}
+
+		order := strings.ToLower(b.order)
+		if order == "" {
+			order = OrderAsc
+		}
+
+		if order != OrderAsc && order != OrderDesc {
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidOrder, b.order)
+		}
+
+		w.WriteString(" ORDER BY ")
+		w.ident(b.orderBy)
+		w.WriteString(" ")
+		w.WriteString(strings.ToUpper(order))
+	}
+
+	if b.limit > 0 {
+		w.WriteString(" LIMIT ")
+		w.placeholder(len(args) + 1)
+		w.WriteString(" OFFSET ")
+		w.placeholder(len(args) + 2)
+
+		args = append(args, b.limit, b.offset)
+	}
+
+	return w.String(), args, nil
+}
+
+// BuildCount 生成相同条件下的 COUNT(*) 语句，忽略排序和分页.
+func (b *SelectBuilder) BuildCount() (string, []interface{}, error) {
+	w, args, err := b.buildFrom(func(w *writer) { w.WriteString("SELECT COUNT(*)") })
+	if err != nil {
+		return "", nil, err
+	}
+
+	return w.String(), args, nil
+}
+
+func (b *SelectBuilder) buildFrom(head func(w *writer)) (*writer, []interface{}, error) {
+	if !identifierRegexp.MatchString(b.table) {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidIdentifier, b.table)
+	}
+
+	w := &writer{dialect: b.dialect}
+	head(w)
+	w.WriteString(" FROM ")
+	w.ident(b.table)
+
+	args := []interface{}{}
+	where := junction{" AND ", b.where}
+
+	if !isEmpty(where) {
+		w.WriteString(" WHERE ")
+
+		var err error
+
+		args, err = where.buildWithParens(w, args, false)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return w, args, nil
+}
@@ -0,0 +1,180 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectBuilder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		b     *SelectBuilder
+		query string
+		args  []interface{}
+		err   error
+	}{
+		{
+			name:  "no condition",
+			b:     Select("user", "id", "username"),
+			query: "SELECT id, username FROM user",
+			args:  []interface{}{},
+		},
+		{
+			name: "and / or / in / like / range",
+			b: Select("user", "id").Where(
+				Eq("status", 1),
+				Or(LikePrefix("username", "adm"), In("email", "a@x.com", "b@x.com")),
+				Between("created_at", "2020-01-01", nil),
+			).Sortable("id").OrderBy("id", "DESC").Page(20, 10),
+			query: "SELECT id FROM user WHERE status = ? AND (username LIKE ? ESCAPE '!' OR email IN (?, ?)) " +
+				"AND created_at >= ? ORDER BY id DESC LIMIT ? OFFSET ?",
+			args: []interface{}{1, "adm%", "a@x.com", "b@x.com", "2020-01-01", int64(10), int64(20)},
+		},
+		{
+			name:  "empty junctions are ignored",
+			b:     Select("user", "id").Where(And(), Or(And()), Eq("id", 1)),
+			query: "SELECT id FROM user WHERE id = ?",
+			args:  []interface{}{1},
+		},
+		{
+			name:  "like escapes wildcards",
+			b:     Select("user", "id").Where(LikePrefix("username", "a%_!")),
+			query: "SELECT id FROM user WHERE username LIKE ? ESCAPE '!'",
+			args:  []interface{}{"a!%!_!!%"},
+		},
+		{
+			name: "unsortable column",
+			b:    Select("user", "id").Sortable("id").OrderBy("password", "asc"),
+			err:  ErrUnsortable,
+		},
+		{
+			name: "invalid order",
+			b:    Select("user", "id").Sortable("id").OrderBy("id", "asc; DROP TABLE user"),
+			err:  ErrInvalidOrder,
+		},
+		{
+			name: "invalid column",
+			b:    Select("user", "id").Where(Eq("id = 1 OR 1", 1)),
+			err:  ErrInvalidIdentifier,
+		},
+		{
+			name: "empty in",
+			b:    Select("user", "id").Where(In("id")),
+			err:  ErrEmptyIn,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, args, err := tc.b.Build()
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.query, query)
+			require.Equal(t, tc.args, args)
+		})
+	}
+}
+
+// 恶意输入只能作为参数传递，不会出现在 SQL 语句中.
+func TestBuildUserQueryHostileInput(t *testing.T) {
+	t.Parallel()
+
+	hostile := "x' OR '1'='1"
+
+	b := buildUserQuery(QueryUserParams{
+		Offset:   0,
+		Limit:    10,
+		Username: hostile,
+		Email:    hostile + "@example.com",
+		Status:   1,
+	})
+
+	query, args, err := b.Build()
+	require.NoError(t, err)
+	require.NotContains(t, query, hostile)
+	require.Equal(t,
+		"SELECT id, username, status, email, password, description, created_at, updated_at, version, deleted_at "+
+			"FROM user WHERE deleted_at IS NULL AND username = ? AND status = ? AND email = ? "+
+			"ORDER BY id ASC LIMIT ? OFFSET ?",
+		query,
+	)
+	require.Equal(t, []interface{}{hostile, int32(1), hostile + "@example.com", int64(10), int64(0)}, args)
+
+	countQuery, countArgs, err := b.BuildCount()
+	require.NoError(t, err)
+	require.Equal(t,
+		"SELECT COUNT(*) FROM user WHERE deleted_at IS NULL AND username = ? AND status = ? AND email = ?", countQuery)
+	require.Equal(t, []interface{}{hostile, int32(1), hostile + "@example.com"}, countArgs)
+
+	for _, orderBy := range []string{"id; DROP TABLE user", "password", "(SELECT 1)"} {
+		_, _, err = buildUserQuery(QueryUserParams{OrderBy: orderBy}).Build()
+		require.ErrorIs(t, err, ErrUnsortable)
+	}
+
+	_, _, err = buildUserQuery(QueryUserParams{Order: "asc, (SELECT SLEEP(10))"}).Build()
+	require.ErrorIs(t, err, ErrInvalidOrder)
+}
+
+func TestBuildUserQueryIncludeDeleted(t *testing.T) {
+	t.Parallel()
+
+	query, args, err := buildUserQuery(QueryUserParams{Limit: 10, IncludeDeleted: true}).BuildCount()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT COUNT(*) FROM user", query)
+	require.Empty(t, args)
+
+	query, _, err = Select("user", "id").Where(IsNotNull("deleted_at")).BuildCount()
+	require.NoError(t, err)
+	require.Equal(t, "SELECT COUNT(*) FROM user WHERE deleted_at IS NOT NULL", query)
+
+	_, _, err = Select("user", "id").Where(IsNull("deleted_at; --")).Build()
+	require.ErrorIs(t, err, ErrInvalidIdentifier)
+}
+
+func TestBuildUserQueryPostgres(t *testing.T) {
+	t.Parallel()
+
+	b := buildUserQuery(QueryUserParams{
+		Offset:   20,
+		Limit:    10,
+		Username: "alice",
+		Status:   1,
+		OrderBy:  "created_at",
+		Order:    "desc",
+	}).Dialect(DialectPostgres)
+
+	query, args, err := b.Build()
+	require.NoError(t, err)
+	require.Equal(t,
+		`SELECT "id", "username", "status", "email", "password", "description", "created_at", "updated_at", `+
+			`"version", "deleted_at" FROM "user" WHERE "deleted_at" IS NULL AND "username" = $1 AND "status" = $2 `+
+			`ORDER BY "created_at" DESC LIMIT $3 OFFSET $4`,
+		query,
+	)
+	require.Equal(t, []interface{}{"alice", int32(1), int64(10), int64(20)}, args)
+
+	countQuery, countArgs, err := b.BuildCount()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT COUNT(*) FROM "user" WHERE "deleted_at" IS NULL AND "username" = $1 AND "status" = $2`,
+		countQuery)
+	require.Equal(t, []interface{}{"alice", int32(1)}, countArgs)
+
+	query, args, err = Select("user", "*").Dialect(DialectPostgres).Where(
+		Or(LikePrefix("user.username", "a_"), In("id", 1, 2)),
+	).Build()
+	require.NoError(t, err)
+	require.Equal(t, `SELECT * FROM "user" WHERE ("user"."username" LIKE $1 ESCAPE '!' OR "id" IN ($2, $3))`, query)
+	require.Equal(t, []interface{}{"a!_%", 1, 2}, args)
+}
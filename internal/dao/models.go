@@ -5,9 +5,36 @@
 package dao
 
 import (
+	"database/sql"
 	"time"
 )
 
+// 角色表
+type Role struct {
+	// 主键id
+	ID int64
+	// 角色名称
+	Name string
+	// 备注
+	Description string
+	// 创建时间
+	CreatedAt time.Time
+	// 更新时间
+	UpdatedAt time.Time
+}
+
+// 角色权限表
+type RolePermission struct {
+	// 主键id
+	ID int64
+	// 角色id
+	RoleID int64
+	// 权限，格式为 resource:action[:self]，支持 * 通配
+	Permission string
+	// 创建时间
+	CreatedAt time.Time
+}
+
 // 用户表
 type User struct {
 	// 主键id
@@ -26,4 +53,20 @@ type User struct {
 	CreatedAt time.Time
 	// 更新时间
 	UpdatedAt time.Time
+	// 乐观锁版本号
+	Version int64
+	// 删除时间，NULL 代表未删除
+	DeletedAt sql.NullTime
+}
+
+// 用户角色关联表
+type UserRole struct {
+	// 主键id
+	ID int64
+	// 用户id
+	UserID int64
+	// 角色id
+	RoleID int64
+	// 创建时间
+	CreatedAt time.Time
 }
@@ -0,0 +1,18 @@
+package dao
+
+// 人工编写的读写分离标记，与数据库类型无关，由支持只读副本的连接（如 mysql.Router）判断.
+import "context"
+
+type ctxKeyPrimary struct{}
+
+// WithPrimary - 之后的查询都读取主库，用于写入之后立即读取（只读副本存在复制延迟）.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyPrimary{}, true)
+}
+
+// UsePrimary - ctx 是否要求查询读取主库.
+func UsePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(ctxKeyPrimary{}).(bool)
+
+	return primary
+}
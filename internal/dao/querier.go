@@ -6,14 +6,26 @@ package dao
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AddRolePermission(ctx context.Context, arg AddRolePermissionParams) error
+	AssignUserRole(ctx context.Context, arg AssignUserRoleParams) error
+	CreateRole(ctx context.Context, arg CreateRoleParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) error
 	DeleteUser(ctx context.Context, username string) error
+	DeleteUserRoles(ctx context.Context, userID int64) error
+	GetRole(ctx context.Context, name string) (Role, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	ListRolePermissions(ctx context.Context, roleID int64) ([]string, error)
 	ListUser(ctx context.Context, arg ListUserParams) ([]User, error)
-	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	ListUserPermissions(ctx context.Context, username string) ([]string, error)
+	ListUserRoles(ctx context.Context, userID int64) ([]Role, error)
+	PurgeDeletedUserRoles(ctx context.Context, before time.Time) error
+	PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error)
+	RestoreUser(ctx context.Context, username string) (int64, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (int64, error)
 	QueryUser(ctx context.Context, arg QueryUserParams) ([]User, int64, error)
 }
 
@@ -0,0 +1,153 @@
+package sqlite
+
+// 人工编写的适配层，将 SQLite 方言生成的查询转换为 dao.Querier，上层 Service 无需感知数据库类型.
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+)
+
+// SQLite 中时间以 UTC 的 "YYYY-MM-DD HH:MM:SS" 文本保存，查询条件需要使用相同的格式比较.
+const timeFormat = "2006-01-02 15:04:05"
+
+// Querier 实现了 dao.Querier.
+type Querier struct {
+	q *Queries
+	// QueryUser 生成的 SQL 与方言无关，复用 dao 中的实现
+	custom *dao.Queries
+}
+
+var _ dao.Querier = (*Querier)(nil)
+
+// New -.
+func NewQuerier(db dao.DBTX) *Querier {
+	return &Querier{
+		q:      New(db),
+		custom: dao.New(db),
+	}
+}
+
+func (q *Querier) AddRolePermission(ctx context.Context, arg dao.AddRolePermissionParams) error {
+	return q.q.AddRolePermission(ctx, AddRolePermissionParams(arg))
+}
+
+func (q *Querier) AssignUserRole(ctx context.Context, arg dao.AssignUserRoleParams) error {
+	return q.q.AssignUserRole(ctx, AssignUserRoleParams(arg))
+}
+
+func (q *Querier) CreateRole(ctx context.Context, arg dao.CreateRoleParams) error {
+	return q.q.CreateRole(ctx, CreateRoleParams(arg))
+}
+
+func (q *Querier) CreateUser(ctx context.Context, arg dao.CreateUserParams) error {
+	return q.q.CreateUser(ctx, CreateUserParams{
+		Username:    arg.Username,
+		Status:      int64(arg.Status),
+		Email:       arg.Email,
+		Password:    arg.Password,
+		Description: arg.Description,
+	})
+}
+
+func (q *Querier) DeleteUser(ctx context.Context, username string) error {
+	return q.q.DeleteUser(ctx, username)
+}
+
+func (q *Querier) DeleteUserRoles(ctx context.Context, userID int64) error {
+	return q.q.DeleteUserRoles(ctx, userID)
+}
+
+func (q *Querier) GetRole(ctx context.Context, name string) (dao.Role, error) {
+	r, err := q.q.GetRole(ctx, name)
+
+	return dao.Role(r), err
+}
+
+func (q *Querier) GetUser(ctx context.Context, username string) (dao.User, error) {
+	u, err := q.q.GetUser(ctx, username)
+
+	return toUser(u), err
+}
+
+func (q *Querier) ListRolePermissions(ctx context.Context, roleID int64) ([]string, error) {
+	return q.q.ListRolePermissions(ctx, roleID)
+}
+
+func (q *Querier) ListUser(ctx context.Context, arg dao.ListUserParams) ([]dao.User, error) {
+	us, err := q.q.ListUser(ctx, ListUserParams{
+		Offset: int64(arg.Offset),
+		Limit:  int64(arg.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]dao.User, len(us))
+	for i, u := range us {
+		users[i] = toUser(u)
+	}
+
+	return users, nil
+}
+
+func (q *Querier) ListUserPermissions(ctx context.Context, username string) ([]string, error) {
+	return q.q.ListUserPermissions(ctx, username)
+}
+
+func (q *Querier) ListUserRoles(ctx context.Context, userID int64) ([]dao.Role, error) {
+	rs, err := q.q.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]dao.Role, len(rs))
+	for i, r := range rs {
+		roles[i] = dao.Role(r)
+	}
+
+	return roles, nil
+}
+
+func (q *Querier) PurgeDeletedUserRoles(ctx context.Context, before time.Time) error {
+	return q.q.PurgeDeletedUserRoles(ctx, before.UTC().Format(timeFormat))
+}
+
+func (q *Querier) PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error) {
+	return q.q.PurgeDeletedUsers(ctx, before.UTC().Format(timeFormat))
+}
+
+func (q *Querier) RestoreUser(ctx context.Context, username string) (int64, error) {
+	return q.q.RestoreUser(ctx, username)
+}
+
+func (q *Querier) UpdateUser(ctx context.Context, arg dao.UpdateUserParams) (int64, error) {
+	return q.q.UpdateUser(ctx, UpdateUserParams{
+		Status:      sql.NullInt64{Int64: int64(arg.Status.Int32), Valid: arg.Status.Valid},
+		Email:       arg.Email,
+		Password:    arg.Password,
+		Description: arg.Description,
+		Username:    arg.Username,
+		Version:     arg.Version,
+	})
+}
+
+func (q *Querier) QueryUser(ctx context.Context, arg dao.QueryUserParams) ([]dao.User, int64, error) {
+	return q.custom.QueryUser(ctx, arg)
+}
+
+func toUser(u User) dao.User {
+	return dao.User{
+		ID:          u.ID,
+		Username:    u.Username,
+		Status:      int32(u.Status),
+		Email:       u.Email,
+		Password:    u.Password,
+		Description: u.Description,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		Version:     u.Version,
+		DeletedAt:   u.DeletedAt,
+	}
+}
@@ -8,6 +8,7 @@ package dao
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :exec
@@ -38,8 +39,12 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 }
 
 const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM user
-WHERE username = ?
+UPDATE user
+SET
+ deleted_at = UTC_TIMESTAMP(),
+ version = version + 1,
+ updated_at = UTC_TIMESTAMP()
+WHERE username = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) DeleteUser(ctx context.Context, username string) error {
@@ -48,8 +53,8 @@ func (q *Queries) DeleteUser(ctx context.Context, username string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, status, email, password, description, created_at, updated_at FROM user
-WHERE username = ? LIMIT 1
+SELECT id, username, status, email, password, description, created_at, updated_at, version, deleted_at FROM user
+WHERE username = ? AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -64,12 +69,15 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const listUser = `-- name: ListUser :many
-SELECT id, username, status, email, password, description, created_at, updated_at FROM user
+SELECT id, username, status, email, password, description, created_at, updated_at, version, deleted_at FROM user
+WHERE deleted_at IS NULL
 ORDER BY id DESC
 LIMIT ?, ?
 `
@@ -97,6 +105,8 @@ func (q *Queries) ListUser(ctx context.Context, arg ListUserParams) ([]User, err
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -111,15 +121,46 @@ func (q *Queries) ListUser(ctx context.Context, arg ListUserParams) ([]User, err
 	return items, nil
 }
 
-const updateUser = `-- name: UpdateUser :exec
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM user
+WHERE deleted_at IS NOT NULL AND deleted_at < CAST(? AS DATETIME)
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE user
+SET
+ deleted_at = NULL,
+ version = version + 1,
+ updated_at = UTC_TIMESTAMP()
+WHERE username = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, username string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreUser, username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUser = `-- name: UpdateUser :execrows
 UPDATE user
 SET
  status = coalesce(?, status),
  email = coalesce(?, email),
  password = coalesce(?, password),
  description = coalesce(?, description),
+ version = version + 1,
  updated_at = UTC_TIMESTAMP()
-WHERE username = ?
+WHERE username = ? AND version = ? AND deleted_at IS NULL
 `
 
 type UpdateUserParams struct {
@@ -128,15 +169,20 @@ type UpdateUserParams struct {
 	Password    sql.NullString
 	Description sql.NullString
 	Username    string
+	Version     int64
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.ExecContext(ctx, updateUser,
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUser,
 		arg.Status,
 		arg.Email,
 		arg.Password,
 		arg.Description,
 		arg.Username,
+		arg.Version,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -3,11 +3,16 @@ package dao
 // 人工编写的查询语句，用于实现 sqlc 无法实现的功能，如 ORDER BY 的自定义排序.
 import (
 	"context"
-	"fmt"
+	"strings"
 )
 
 const (
-	and = ` AND `
+	userTable   = "user"
+	userColumns = "id, username, status, email, password, description, created_at, updated_at, version, deleted_at"
+
+	// 与 sqlc 生成的语句保持一致的名称注释，用于 SQL 指标的 query label
+	queryUserCountName = "-- name: QueryUserCount :one\n"
+	queryUserName      = "-- name: QueryUser :many\n"
 )
 
 type QueryUserParams struct {
@@ -18,14 +23,32 @@ type QueryUserParams struct {
 	Username string // 空字符串代表全部匹配
 	Status   int32  // 0 则搜索全部状态
 	Email    string // 空字符串代表全部匹配
+	// 是否包含已软删除的用户
+	IncludeDeleted bool
+}
+
+func (q *Queries) QueryUser(ctx context.Context, arg QueryUserParams) ([]User, int64, error) {
+	return QueryUserWithDialect(ctx, q.db, DialectMySQL, arg)
 }
 
-func (q *Queries) QueryUser(ctx context.Context, arg QueryUserParams) (items []User, count int64, err error) {
-	countSQL := buildUserQuerySQL(arg, true)
-	querySQL := buildUserQuerySQL(arg, false)
+// QueryUserWithDialect - 其他方言的 Querier（如 PostgreSQL）复用相同的查询逻辑.
+func QueryUserWithDialect(
+	ctx context.Context, db DBTX, d Dialect, arg QueryUserParams,
+) (items []User, count int64, err error) {
+	b := buildUserQuery(arg).Dialect(d)
+
+	countSQL, countArgs, err := b.BuildCount()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	querySQL, queryArgs, err := b.Build()
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// 计算 Count
-	row := q.db.QueryRowContext(ctx, countSQL)
+	row := db.QueryRowContext(ctx, queryUserCountName+countSQL, countArgs...)
 
 	err = row.Scan(&count)
 	if err != nil {
@@ -33,7 +56,7 @@ func (q *Queries) QueryUser(ctx context.Context, arg QueryUserParams) (items []U
 	}
 
 	// 进行查询
-	rows, err := q.db.QueryContext(ctx, querySQL, arg.Offset, arg.Limit)
+	rows, err := db.QueryContext(ctx, queryUserName+querySQL, queryArgs...)
 	if err != nil {
 		return nil, count, err
 	}
@@ -52,6 +75,8 @@ func (q *Queries) QueryUser(ctx context.Context, arg QueryUserParams) (items []U
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, count, err
 		}
@@ -70,44 +95,30 @@ func (q *Queries) QueryUser(ctx context.Context, arg QueryUserParams) (items []U
 	return items, count, nil
 }
 
-func buildUserQuerySQL(arg QueryUserParams, isCount bool) (sql string) {
-	if isCount {
-		sql = `SELECT COUNT(*) FROM user`
-	} else {
-		sql = `SELECT * FROM user`
-	}
+// 构建用户查询，排序字段只允许白名单中的列.
+func buildUserQuery(arg QueryUserParams) *SelectBuilder {
+	b := Select(userTable, strings.Split(userColumns, ", ")...).
+		Sortable("id", "username", "created_at", "updated_at")
 
-	if arg.Username != "" || arg.Status != 0 || arg.Email != "" {
-		sql += ` WHERE `
+	if !arg.IncludeDeleted {
+		b.Where(IsNull("deleted_at"))
 	}
 
-	connect := ""
 	if arg.Username != "" {
-		sql += fmt.Sprintf(` %s username = '%s'`, connect, arg.Username)
-		connect = and
+		b.Where(Eq("username", arg.Username))
 	}
 
 	if arg.Status != 0 {
-		sql += fmt.Sprintf(` %s status = %d`, connect, arg.Status)
-		connect = and
+		b.Where(Eq("status", arg.Status))
 	}
 
 	if arg.Email != "" {
-		sql += fmt.Sprintf(` %s email = '%s'`, connect, arg.Email)
+		b.Where(Eq("email", arg.Email))
 	}
 
 	if arg.OrderBy == "" {
 		arg.OrderBy = "id"
 	}
 
-	if arg.Order == "" {
-		arg.Order = "asc"
-	}
-
-	if !isCount {
-		sql += ` ORDER BY ` + arg.OrderBy + ` ` + arg.Order
-		sql += ` LIMIT ?, ?`
-	}
-
-	return sql
+	return b.OrderBy(arg.OrderBy, arg.Order).Page(arg.Offset, arg.Limit)
 }
@@ -0,0 +1,21 @@
+package entity
+
+// Token 是登录或刷新后签发给客户端的凭证.
+type Token struct {
+	// 访问令牌，放到 Authorization: Bearer <token> 中
+	AccessToken string `example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.e30.xxx" json:"accessToken"`
+	// 令牌类型，固定为 Bearer
+	TokenType string `example:"Bearer" json:"tokenType"`
+	// 访问令牌的有效时长（秒）
+	ExpiresIn int64 `example:"900" json:"expiresIn"`
+	// 刷新令牌，只能使用一次
+	RefreshToken string `example:"3q2-7wEAAAC6ZG9Xb3JsZA" json:"refreshToken"`
+}
+
+// Principal 是通过认证的调用方.
+type Principal struct {
+	// 用户名，即 Access Token 的 subject
+	Username string `json:"username"`
+	// Access Token 的 ID (jti)
+	TokenID string `json:"tokenId"`
+}
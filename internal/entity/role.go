@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+)
+
+const (
+	// RoleAdmin 管理员，拥有全部权限.
+	RoleAdmin = "admin"
+	// RoleUser 普通用户，只能读取和修改自己.
+	RoleUser = "user"
+)
+
+// 权限的格式为 <resource>:<action>，"*" 代表全部权限，"<resource>:*" 代表该资源的全部权限.
+// 在权限后追加 ":self" 代表仅当操作的对象是自己时才有该权限，如 "user:read:self".
+const (
+	PermissionAll = "*"
+	// PermissionSelfSuffix 仅对自己生效的权限后缀.
+	PermissionSelfSuffix = ":self"
+
+	PermUserCreate = "user:create"
+	PermUserList   = "user:list"
+	PermUserRead   = "user:read"
+	PermUserUpdate = "user:update"
+	PermUserDelete = "user:delete"
+	// 恢复已删除的用户
+	PermUserRestore = "user:restore"
+	// 列表中查询已删除的用户
+	PermUserListDeleted = "user:list-deleted"
+)
+
+// Role Entity.
+type Role struct {
+	// DB id.
+	ID int64 `example:"1" json:"-"`
+	// 角色名称
+	Name string `example:"admin" json:"name"`
+	// 备注
+	Description string `example:"Administrator" json:"description"`
+	// 权限列表
+	Permissions []string `example:"user:read" json:"permissions"`
+	// 创建时间
+	CreatedAt time.Time `example:"2020-01-01T00:00:00Z" json:"createdAt"`
+	// 更新时间
+	UpdatedAt time.Time `example:"2020-01-01T00:00:00Z" json:"updatedAt"`
+}
+
+// 内置的角色，服务启动时自动创建.
+func DefaultRoles() []Role {
+	return []Role{
+		{
+			Name:        RoleAdmin,
+			Description: "Administrator, has all permissions",
+			Permissions: []string{PermissionAll},
+		},
+		{
+			Name:        RoleUser,
+			Description: "Normal user, can only read and update self",
+			Permissions: []string{
+				PermUserRead + PermissionSelfSuffix,
+				PermUserUpdate + PermissionSelfSuffix,
+			},
+		},
+	}
+}
+
+// 将 dao.models.Role 转为 entity.Role, 权限需要单独查询.
+func ToRole(role dao.Role, permissions []string) Role {
+	return Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
@@ -31,6 +31,10 @@ type User struct {
 	CreatedAt time.Time `example:"2020-01-01T00:00:00Z" json:"createdAt"`
 	// 更新时间
 	UpdatedAt time.Time `example:"2020-01-01T00:00:00Z" json:"updatedAt"`
+	// 删除时间，只有查询已删除的用户时才会出现
+	DeletedAt *time.Time `example:"2020-01-01T00:00:00Z" json:"deletedAt,omitempty"`
+	// 乐观锁版本号，通过 ETag 响应头输出；更新时不为 0 则要求与当前版本一致
+	Version int64 `json:"-"`
 }
 
 func (u *User) IsActive() bool {
@@ -39,6 +43,11 @@ func (u *User) IsActive() bool {
 
 // 将 dao.models.User 转为 entity.User, 忽略Password 字段.
 func ToUser(user dao.User) User {
+	var deletedAt *time.Time
+	if user.DeletedAt.Valid {
+		deletedAt = &user.DeletedAt.Time
+	}
+
 	return User{
 		ID:          user.ID,
 		Username:    user.Username,
@@ -47,12 +56,28 @@ func ToUser(user dao.User) User {
 		Description: user.Description,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		DeletedAt:   deletedAt,
+		Version:     user.Version,
 	}
 }
 
+// 部分更新 User，字段为 nil 代表不修改，非 nil 的空字符串代表清空.
+type UserPatch struct {
+	Username    string
+	Status      *int32
+	Email       *string
+	Description *string
+	// 明文密码，由 Service 加密
+	Password *string
+	// 期望的版本号，不为 0 时要求与当前版本一致
+	Version int64
+}
+
 // 用户查询条件.
 type UserQuery struct {
 	Username string `json:"username"`
 	Status   int32  `json:"status"`
 	Email    string `json:"email"`
+	// 包含已删除的用户
+	IncludeDeleted bool `json:"includeDeleted"`
 }
@@ -1,23 +1,67 @@
 package dependency
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/go-redis/redis/v8"
 
 	"github.com/ninehills/go-webapp-template/config"
 	"github.com/ninehills/go-webapp-template/internal/dao"
+	daopostgres "github.com/ninehills/go-webapp-template/internal/dao/postgres"
+	daosqlite "github.com/ninehills/go-webapp-template/internal/dao/sqlite"
 	"github.com/ninehills/go-webapp-template/pkg/cache"
+	"github.com/ninehills/go-webapp-template/pkg/health"
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+	"github.com/ninehills/go-webapp-template/pkg/lock"
 	"github.com/ninehills/go-webapp-template/pkg/logger"
 	"github.com/ninehills/go-webapp-template/pkg/mysql"
+	"github.com/ninehills/go-webapp-template/pkg/postgres"
+	"github.com/ninehills/go-webapp-template/pkg/ratelimit"
+	"github.com/ninehills/go-webapp-template/pkg/sqlite"
+	"github.com/ninehills/go-webapp-template/pkg/token"
+	"github.com/ninehills/go-webapp-template/pkg/tracing"
+	"github.com/ninehills/go-webapp-template/pkg/version"
 )
 
+const tracerShutdownTimeout = 5 * time.Second
+
+var errUnknownCacheBackend = errors.New("unknown cache backend")
+
 // 全局依赖.
 type Dependency struct {
 	Config *config.Config
 	Logger logger.Logger
-	MySQL  *mysql.MySQL
-	DAO    dao.Querier
+	// 当前使用的数据库连接，由 database.driver 决定是 MySQL、PostgreSQL 还是 SQLite
+	DB       *sql.DB
+	MySQL    *mysql.MySQL
+	Postgres *postgres.Postgres
+	SQLite   *sqlite.SQLite
+	DAO      dao.Querier
+	// 未配置 redis.url 时为 nil，Locker、KV 和 RateLimiter 使用进程内实现
 	Redis  *redis.Client
 	Cache  cache.Cacher
+	Token  *token.Manager
+	Health *health.Checker
+	Locker *lock.Locker
+	Tracer *tracing.Tracing
+	// 幂等记录和 Refresh Token 的存储
+	KV kv.Store
+	// 仅 tiered 缓存使用，其他后端为 nil
+	CacheBus *cache.Bus
+	// Redis 限流，Redis 不可用时降级为进程内限流
+	RateLimiter ratelimit.Limiter
+	// 按名称声明的限流规则，随配置热加载
+	RateLimits *ratelimit.Rules
+}
+
+// 配置文件变更后动态加载.
+func (d *Dependency) Reload(cfg *config.Config) {
+	d.ReloadLogger(cfg)
+	d.ReloadRateLimits(cfg)
 }
 
 // 动态加载日志级别.
@@ -36,6 +80,12 @@ func (d *Dependency) ReloadLogger(cfg *config.Config) {
 	d.Logger = l
 }
 
+// 动态加载限流规则.
+func (d *Dependency) ReloadRateLimits(cfg *config.Config) {
+	d.RateLimits.Store(rateLimitRules(cfg))
+	d.Logger.Infof("base - ReloadRateLimits - enabled[%t] rules[%d]", cfg.RateLimit.Enabled, len(cfg.RateLimit.Rules))
+}
+
 // 初始化全局依赖.
 func NewDependency(cfg *config.Config) *Dependency {
 	// 初始化日志 logger
@@ -48,46 +98,228 @@ func NewDependency(cfg *config.Config) *Dependency {
 	// Override the global standard library logger to make sure everything uses our logger
 	logger.SetStandardLogger(l)
 
-	// 初始化 MySQL 数据库
-	ms, err := mysql.New(
-		l,
-		cfg.MySQL.DSN,
-		mysql.ConnMaxLifetime(cfg.MySQL.ConnMaxLifetime),
-		mysql.MaxOpenConns(cfg.MySQL.MaxOpenConns),
-		mysql.MaxIdleConns(cfg.MySQL.MaxIdleConns),
+	// 注册 build_info 指标
+	err := version.RegisterBuildInfo()
+	if err != nil {
+		l.Errorf("base - NewDependency - version.RegisterBuildInfo: %w", err)
+		panic(err)
+	}
+
+	// 初始化 Tracing，需要在 MySQL/Redis 之前，保证 hook 使用的是全局 TracerProvider
+	tr, err := tracing.New(
+		cfg.App.Name,
+		tracing.Exporter(cfg.Tracing.Exporter),
+		tracing.Endpoint(cfg.Tracing.Endpoint),
+		tracing.Insecure(cfg.Tracing.Insecure),
+		tracing.File(cfg.Tracing.File),
+		tracing.SampleRatio(cfg.Tracing.SampleRatio),
 	)
 	if err != nil {
-		l.Errorf("base - NewDependency - mysql.New: %w", err)
+		l.Errorf("base - NewDependency - tracing.New: %w", err)
 		panic(err)
 	}
-	// Dao 初始化，生成 queries 对象
-	queries := dao.New(ms.DB)
 
-	// 初始化 Redis 数据库
-	opt, err := redis.ParseURL(cfg.Redis.URL)
+	// 初始化数据库，Dao 初始化，生成 queries 对象
+	deps := Dependency{Config: cfg, Logger: l}
+
+	err = deps.openDatabase(cfg)
 	if err != nil {
-		l.Errorf("base - NewDependency - redis parse url failed: %w", err)
+		l.Errorf("base - NewDependency - openDatabase: %w", err)
 		panic(err)
 	}
 
-	rdb := redis.NewClient(opt)
+	// 初始化 Redis 数据库，未配置时为 nil
+	rdb, err := newRedis(cfg)
+	if err != nil {
+		l.Errorf("base - NewDependency - redis parse url failed: %w", err)
+		panic(err)
+	}
 
 	// 初始化 Cache，默认过期时间是5分钟
-	c := cache.NewCache(rdb, cache.DefaultCacheExpires)
+	c, bus, err := newCache(cfg, rdb, l)
+	if err != nil {
+		l.Errorf("base - NewDependency - newCache: %w", err)
+		panic(err)
+	}
+
+	// 初始化 Token 签发器
+	tm := token.New(
+		cfg.Auth.Secret,
+		token.Issuer(cfg.Auth.Issuer),
+		token.AccessTokenTTL(cfg.Auth.AccessTokenTTL),
+		token.RefreshTokenTTL(cfg.Auth.RefreshTokenTTL),
+	)
+
+	// 注册就绪检查
+	hc := health.New()
+	hc.Register(cfg.Database.Driver, deps.DB.PingContext)
+
+	// 未配置 Redis 时，锁、限流和 KV 都使用进程内实现，只能部署单个实例
+	var rl ratelimit.Limiter = ratelimit.NewMemory()
+
+	deps.Locker = lock.NewMemory()
+	deps.KV = kv.NewMemory()
 
-	deps := Dependency{
-		Config: cfg,
-		Logger: l,
-		MySQL:  ms,
-		DAO:    queries,
-		Redis:  rdb,
-		Cache:  c,
+	if rdb != nil {
+		hc.Register("redis", func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		})
+
+		rl = ratelimit.NewFallback(ratelimit.NewRedis(rdb), ratelimit.NewMemory(), l)
+		deps.Locker = lock.New(rdb)
+		deps.KV = kv.NewRedis(rdb)
 	}
 
+	deps.Redis = rdb
+	deps.Cache = c
+	deps.Token = tm
+	deps.Health = hc
+	deps.Tracer = tr
+	deps.CacheBus = bus
+	deps.RateLimiter = rl
+	deps.RateLimits = ratelimit.NewRules(rateLimitRules(cfg))
+
 	return &deps
 }
 
+// 根据 redis.url 创建 Redis 客户端，为空时返回 nil.
+func newRedis(cfg *config.Config) (*redis.Client, error) {
+	if cfg.Redis.URL == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	opt, err := redis.ParseURL(cfg.Redis.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return redis.NewClient(opt), nil
+}
+
+// 根据 database.driver 打开数据库.
+func (d *Dependency) openDatabase(cfg *config.Config) error {
+	switch cfg.Database.Driver {
+	case "sqlite":
+		sq, err := sqlite.New(d.Logger, cfg.SQLite.Path)
+		if err != nil {
+			return fmt.Errorf("sqlite.New: %w", err)
+		}
+
+		d.SQLite, d.DB = sq, sq.DB
+	case "postgres":
+		pg, err := postgres.New(
+			d.Logger,
+			cfg.Postgres.DSN,
+			postgres.ConnMaxLifetime(cfg.Postgres.ConnMaxLifetime),
+			postgres.MaxOpenConns(cfg.Postgres.MaxOpenConns),
+			postgres.MaxIdleConns(cfg.Postgres.MaxIdleConns),
+		)
+		if err != nil {
+			return fmt.Errorf("postgres.New: %w", err)
+		}
+
+		d.Postgres, d.DB = pg, pg.DB
+	default:
+		ms, err := mysql.New(
+			d.Logger,
+			cfg.MySQL.DSN,
+			mysql.ConnMaxLifetime(cfg.MySQL.ConnMaxLifetime),
+			mysql.MaxOpenConns(cfg.MySQL.MaxOpenConns),
+			mysql.MaxIdleConns(cfg.MySQL.MaxIdleConns),
+			mysql.Replicas(cfg.MySQL.ReplicaDSNs...),
+			mysql.ReplicaCheckInterval(cfg.MySQL.ReplicaCheckInterval),
+			mysql.ReadPrimary(dao.UsePrimary),
+		)
+		if err != nil {
+			return fmt.Errorf("mysql.New: %w", err)
+		}
+
+		d.MySQL, d.DB = ms, ms.DB
+	}
+
+	// 事务之外的查询通过 Router 分发到只读副本，事务由 TxManager 在主库 d.DB 上开启
+	if d.MySQL != nil {
+		d.DAO = d.Querier(d.MySQL.Router)
+	} else {
+		d.DAO = d.Querier(d.DB)
+	}
+
+	return nil
+}
+
+// 基于连接或事务创建当前数据库方言的 Querier.
+func (d *Dependency) Querier(db dao.DBTX) dao.Querier {
+	switch d.Config.Database.Driver {
+	case "sqlite":
+		return daosqlite.NewQuerier(db)
+	case "postgres":
+		return daopostgres.NewQuerier(db)
+	}
+
+	return dao.New(db)
+}
+
+// 根据配置选择 Cache 后端，redis 和 tiered 后端要求配置 Redis（由 Config.Validate 保证）.
+// tiered 后端会订阅失效广播，写入和删除时通知其他实例删除本地缓存.
+func newCache(cfg *config.Config, rdb *redis.Client, l logger.Logger) (cache.Cacher, *cache.Bus, error) {
+	switch cfg.Cache.Backend {
+	case "memory":
+		return cache.NewMemory(cfg.Cache.Size, cache.DefaultCacheExpires), nil, nil
+	case "redis":
+		return cache.NewCache(rdb, cache.DefaultCacheExpires), nil, nil
+	case "tiered":
+		localTTL := time.Duration(cfg.Cache.LocalTTL) * time.Second
+		local := cache.NewMemory(cfg.Cache.Size, localTTL)
+		bus := cache.NewBus(rdb, cfg.Cache.Channel, local, l)
+
+		return cache.NewTiered(local, cache.NewCache(rdb, cache.DefaultCacheExpires), bus, localTTL), bus, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", errUnknownCacheBackend, cfg.Cache.Backend)
+	}
+}
+
+// 将配置转换为限流规则，关闭限流时返回空规则.
+func rateLimitRules(cfg *config.Config) map[string]ratelimit.Rule {
+	rules := make(map[string]ratelimit.Rule, len(cfg.RateLimit.Rules))
+	if !cfg.RateLimit.Enabled {
+		return rules
+	}
+
+	for name, r := range cfg.RateLimit.Rules {
+		rules[name] = ratelimit.Rule{
+			Limit: ratelimit.Limit{
+				Algorithm: r.Algorithm,
+				Rate:      r.Rate,
+				Period:    time.Duration(r.Period) * time.Second,
+				Burst:     r.Burst,
+			},
+			Key: r.Key,
+		}
+	}
+
+	return rules
+}
+
 func (d *Dependency) Close() {
-	d.MySQL.DB.Close()
-	d.Redis.Close()
+	if d.CacheBus != nil {
+		d.CacheBus.Close()
+	}
+
+	if d.MySQL != nil {
+		d.MySQL.Close()
+	} else {
+		d.DB.Close()
+	}
+
+	if d.Redis != nil {
+		d.Redis.Close()
+	}
+
+	// 刷出未导出的 span
+	ctx, cancel := context.WithTimeout(context.Background(), tracerShutdownTimeout)
+	defer cancel()
+
+	if err := d.Tracer.Shutdown(ctx); err != nil {
+		d.Logger.Errorf("base - Close - tracing.Shutdown: %v", err)
+	}
 }
@@ -0,0 +1,46 @@
+package exception
+
+import (
+	"fmt"
+
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+	"github.com/ninehills/go-webapp-template/pkg/postgres"
+	"github.com/ninehills/go-webapp-template/pkg/sqlite"
+)
+
+// 将 MySQL / PostgreSQL / SQLite 错误转换为对应的 HTTP 错误，无法识别时返回 nil，由调用方按内部错误处理.
+// 唯一索引使用字段名命名（如 `email` varchar(255) UNIQUE KEY），冲突时字段即为索引名.
+func FromDB(err error) *Error {
+	e := mysql.Classify(err)
+	if e == nil {
+		e = postgres.Classify(err)
+	}
+
+	if e == nil {
+		e = sqlite.Classify(err)
+	}
+
+	if e == nil {
+		return nil
+	}
+
+	switch e.Kind {
+	case mysql.KindDuplicate:
+		if e.Index == "" || e.Index == "PRIMARY" {
+			return Conflict(fmt.Errorf("resource already exists"))
+		}
+
+		return Conflict(fmt.Errorf("%s already in use", e.Index)).WithField(e.Index)
+	case mysql.KindRowIsReferenced:
+		return Conflict(fmt.Errorf("resource is still referenced by %s", e.Index))
+	case mysql.KindNoReferencedRow:
+		return BadRequest(fmt.Errorf("referenced resource of %s does not exist", e.Index))
+	case mysql.KindDeadlock, mysql.KindLockWaitTimeout:
+		return Conflict(fmt.Errorf("resource is being modified concurrently, please retry"))
+	case mysql.KindConnection:
+		return ServiceUnavailable(fmt.Errorf("database is unavailable, please retry later"))
+	case mysql.KindUnknown:
+	}
+
+	return nil
+}
@@ -12,6 +12,8 @@ type Error struct {
 	code int
 	// 内部封装的错误
 	err error
+	// 导致错误的请求字段，如 email，可以为空
+	field string
 }
 
 func (e *Error) Error() string {
@@ -22,12 +24,23 @@ func (e *Error) Code() int {
 	return e.code
 }
 
+func (e *Error) Field() string {
+	return e.field
+}
+
 func (e *Error) Unwarp() error {
 	return e.err
 }
 
+// 设置导致错误的请求字段.
+func (e *Error) WithField(field string) *Error {
+	e.field = field
+
+	return e
+}
+
 func NewError(code int, err error) *Error {
-	return &Error{code, err}
+	return &Error{code: code, err: err}
 }
 
 // http.StatusConflict.
@@ -45,11 +58,26 @@ func Unauthorized(err error) *Error {
 	return NewError(http.StatusUnauthorized, err)
 }
 
+// http.StatusForbidden.
+func Forbidden(err error) *Error {
+	return NewError(http.StatusForbidden, err)
+}
+
 // http.StatusNotFound.
 func NotFound(err error) *Error {
 	return NewError(http.StatusNotFound, err)
 }
 
+// http.StatusPreconditionFailed.
+func PreconditionFailed(err error) *Error {
+	return NewError(http.StatusPreconditionFailed, err)
+}
+
+// http.StatusServiceUnavailable.
+func ServiceUnavailable(err error) *Error {
+	return NewError(http.StatusServiceUnavailable, err)
+}
+
 // http.StatusInternalServerError.
 func InternalServer(err error) *Error {
 	return NewError(http.StatusInternalServerError, err)
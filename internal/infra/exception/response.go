@@ -22,7 +22,13 @@ func CodeResponse(c *gin.Context, code int, msg string) {
 func ResponseWithError(c *gin.Context, err error) {
 	var e *Error
 	if errors.As(err, &e) {
-		CodeResponse(c, e.Code(), e.Error())
+		// 带有字段时返回给客户端，便于定位到具体的输入项
+		c.AbortWithStatusJSON(
+			e.Code(),
+			httpv1.ErrorResponse{
+				Message: e.Error(), Code: http.StatusText(e.Code()), Field: e.Field(), RequestID: requestid.Get(c),
+			},
+		)
 	} else {
 		CodeResponse(c, http.StatusInternalServerError, err.Error())
 	}
@@ -31,6 +31,12 @@ func (a *AuditMiddleware) Audit() gin.HandlerFunc {
 		}
 		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 		c.Next()
+
+		operator := ""
+		if p, ok := GetPrincipal(c); ok {
+			operator = p.Username
+		}
+
 		a.l.Info(
 			"AUDIT_LOG",
 			map[string]interface{}{
@@ -44,6 +50,7 @@ func (a *AuditMiddleware) Audit() gin.HandlerFunc {
 				"user_agent":  c.Request.UserAgent(),
 				"raw_query":   c.Request.URL.RawQuery,
 				"request_id":  requestid.Get(c),
+				"operator":    operator,
 			},
 		)
 	}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/token"
+)
+
+const (
+	principalKey = "auth.principal"
+	bearerPrefix = "Bearer "
+)
+
+var (
+	errMissingToken = errors.New("missing bearer token")
+	errInvalidToken = errors.New("invalid or expired access token")
+)
+
+type AuthMiddleware struct {
+	l     logger.Logger
+	token *token.Manager
+}
+
+func NewAuthMiddleware(l logger.Logger, tm *token.Manager) *AuthMiddleware {
+	return &AuthMiddleware{
+		l:     l,
+		token: tm,
+	}
+}
+
+// 返回认证中间件，要求请求携带合法的 Bearer Token，并将 Principal 写入 gin.Context.
+func (a *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			unauthorized(c, errMissingToken)
+
+			return
+		}
+
+		claims, err := a.token.ParseAccessToken(raw)
+		if err != nil {
+			a.l.Ctx(c).Err(err).Info("middleware - RequireSession - parse access token failed")
+			unauthorized(c, errInvalidToken)
+
+			return
+		}
+
+		c.Set(principalKey, entity.Principal{
+			Username: claims.Subject,
+			TokenID:  claims.ID,
+		})
+		c.Next()
+	}
+}
+
+// 获取当前请求的 Principal，只有挂载了 RequireSession 的路由才会存在.
+func GetPrincipal(c *gin.Context) (entity.Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return entity.Principal{}, false
+	}
+
+	p, ok := value.(entity.Principal)
+
+	return p, ok
+}
+
+func bearerToken(header string) (string, bool) {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
+
+func unauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	exception.ResponseWithError(c, exception.Unauthorized(err))
+}
@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/token"
+)
+
+func TestRequireSession(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	tm := token.New("secret")
+	valid, _, err := tm.IssueAccessToken("admin")
+	require.NoError(t, err)
+
+	auth := middleware.NewAuthMiddleware(logger.New(logger.Config{Level: "error"}), tm)
+	handler := gin.New()
+	handler.GET("/private", auth.RequireSession(), func(c *gin.Context) {
+		p, ok := middleware.GetPrincipal(c)
+		require.True(t, ok)
+		c.String(http.StatusOK, p.Username)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{name: "missing header", header: "", code: http.StatusUnauthorized},
+		{name: "wrong scheme", header: "Basic " + valid, code: http.StatusUnauthorized},
+		{name: "invalid token", header: "Bearer xxx", code: http.StatusUnauthorized},
+		{name: "valid token", header: "Bearer " + valid, code: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/private", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tc.code, w.Code)
+		})
+	}
+}
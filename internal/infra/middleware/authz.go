@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+type AuthzMiddleware struct {
+	l     logger.Logger
+	authz service.Authz
+}
+
+func NewAuthzMiddleware(l logger.Logger, authz service.Authz) *AuthzMiddleware {
+	return &AuthzMiddleware{
+		l:     l,
+		authz: authz,
+	}
+}
+
+// 返回鉴权中间件，要求当前用户拥有 action 权限，需要挂载在 RequireSession 之后.
+func (a *AuthzMiddleware) Require(action string) gin.HandlerFunc {
+	return a.require(action, "")
+}
+
+// 同 Require，但是将路由参数 param 作为操作的对象，用于支持 ":self" 权限.
+func (a *AuthzMiddleware) RequireOn(action, param string) gin.HandlerFunc {
+	return a.require(action, param)
+}
+
+func (a *AuthzMiddleware) require(action, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := GetPrincipal(c)
+		if !ok {
+			unauthorized(c, errMissingToken)
+
+			return
+		}
+
+		resource := ""
+		if param != "" {
+			resource = c.Param(param)
+		}
+
+		allowed, err := a.authz.Can(c, p, action, resource)
+		if err != nil {
+			a.l.Ctx(c).Err(err).Error("middleware - Require - check permission failed")
+			exception.ResponseWithError(c, err)
+
+			return
+		}
+
+		if !allowed {
+			exception.ResponseWithError(c, exception.Forbidden(fmt.Errorf("permission denied: %s", action)))
+
+			return
+		}
+
+		c.Next()
+	}
+}
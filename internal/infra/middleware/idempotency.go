@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+	"github.com/ninehills/go-webapp-template/pkg/lock"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyKeyPrefix      = "idempotency:"
+	idempotencyKeyMaxLength   = 255
+	idempotencyRecordTTL      = 24 * time.Hour
+	idempotencyLockTTL        = 30 * time.Second
+	idempotencyInFlightWait   = 10 * time.Second
+	idempotencyStoreTimeout   = 3 * time.Second
+	idempotencyAnonymousScope = "-"
+)
+
+// 已完成请求的响应，重试时原样返回.
+type idempotencyRecord struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+type IdempotencyMiddleware struct {
+	l      logger.Logger
+	store  kv.Store
+	locker *lock.Locker
+}
+
+func NewIdempotencyMiddleware(l logger.Logger, store kv.Store, locker *lock.Locker) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		l:      l,
+		store:  store,
+		locker: locker,
+	}
+}
+
+// 返回幂等中间件，请求带有 Idempotency-Key 时，首次请求的响应（状态码、响应头、响应体）会保存在 store 中，
+// 相同 key 且请求指纹（方法、路径、请求体）一致的重试直接返回保存的响应，指纹不一致时返回 422.
+// 并发的相同请求通过分布式锁串行化，后到的请求等待首次请求完成后返回其响应.
+// 5xx 响应不会被保存，客户端可以使用相同的 key 重试.
+func (m *IdempotencyMiddleware) Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+
+			return
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			exception.CodeResponse(c, http.StatusBadRequest, "Idempotency-Key is too long")
+
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			exception.CodeResponse(c, http.StatusBadRequest, "invalid request body")
+
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		// 不同用户之间的 key 相互隔离
+		scope := idempotencyAnonymousScope
+		if p, ok := GetPrincipal(c); ok {
+			scope = p.Username
+		}
+
+		storeKey := idempotencyKeyPrefix + scope + ":" + key
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		if m.replay(c, storeKey, fingerprint) {
+			return
+		}
+
+		// 同一个 key 的请求正在处理中，等待其完成
+		waitCtx, cancel := context.WithTimeout(c, idempotencyInFlightWait)
+		defer cancel()
+
+		l, err := m.locker.Acquire(waitCtx, storeKey, idempotencyLockTTL)
+		if errors.Is(err, lock.ErrNotAcquired) {
+			exception.CodeResponse(c, http.StatusConflict, "a request with the same Idempotency-Key is in progress")
+
+			return
+		} else if err != nil {
+			// 存储不可用时放弃幂等保证，不影响正常请求
+			m.l.Ctx(c).Err(err).Error("middleware - Idempotent - acquire lock failed")
+			c.Next()
+
+			return
+		}
+
+		defer func() {
+			if err := l.Release(context.Background()); err != nil {
+				m.l.Ctx(c).Err(err).Warn("middleware - Idempotent - release lock failed")
+			}
+		}()
+
+		// 拿到锁后再检查一次，并发的首次请求可能已经完成
+		if m.replay(c, storeKey, fingerprint) {
+			return
+		}
+
+		m.record(c, storeKey, fingerprint)
+	}
+}
+
+// 存在已保存的响应时写回并返回 true.
+func (m *IdempotencyMiddleware) replay(c *gin.Context, storeKey, fingerprint string) bool {
+	raw, err := m.store.Get(c, storeKey)
+	if errors.Is(err, kv.ErrNotFound) {
+		return false
+	} else if err != nil {
+		m.l.Ctx(c).Err(err).Error("middleware - Idempotent - get record failed")
+
+		return false
+	}
+
+	var r idempotencyRecord
+	if err := json.Unmarshal(raw, &r); err != nil {
+		m.l.Ctx(c).Err(err).Error("middleware - Idempotent - unmarshal record failed")
+
+		return false
+	}
+
+	if r.Fingerprint != fingerprint {
+		exception.CodeResponse(c, http.StatusUnprocessableEntity,
+			"Idempotency-Key has already been used with a different request")
+
+		return true
+	}
+
+	for k, values := range r.Header {
+		for _, v := range values {
+			c.Writer.Header().Add(k, v)
+		}
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Writer.WriteHeader(r.Status)
+	_, _ = c.Writer.Write(r.Body)
+	c.Abort()
+
+	return true
+}
+
+// 执行后续的 handler，并保存响应.
+func (m *IdempotencyMiddleware) record(c *gin.Context, storeKey, fingerprint string) {
+	// 只保存 handler 设置的响应头，之前的中间件设置的（如 X-Request-Id）在重放时会重新生成
+	before := make(map[string]bool, len(c.Writer.Header()))
+	for k := range c.Writer.Header() {
+		before[k] = true
+	}
+
+	w := &bodyRecorder{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+
+	if w.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	header := http.Header{}
+
+	for k, v := range w.Header() {
+		if !before[k] {
+			header[k] = v
+		}
+	}
+
+	raw, err := json.Marshal(idempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      w.Status(),
+		Header:      header,
+		Body:        w.body.Bytes(),
+	})
+	if err != nil {
+		m.l.Ctx(c).Err(err).Error("middleware - Idempotent - marshal record failed")
+
+		return
+	}
+
+	// 请求可能已经被客户端取消，保存时不使用请求的 ctx
+	ctx, cancel := context.WithTimeout(context.Background(), idempotencyStoreTimeout)
+	defer cancel()
+
+	if err := m.store.Set(ctx, storeKey, raw, idempotencyRecordTTL); err != nil {
+		m.l.Ctx(c).Err(err).Error("middleware - Idempotent - save record failed")
+	}
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// 记录写入的响应体.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
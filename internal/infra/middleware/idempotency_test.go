@@ -0,0 +1,153 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+	"github.com/ninehills/go-webapp-template/pkg/lock"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+func newIdempotentHandler(t *testing.T, calls *int32, delay time.Duration) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	idem := middleware.NewIdempotencyMiddleware(
+		logger.New(logger.Config{Level: "error"}), kv.NewRedis(rdb), lock.New(rdb, lock.RetryInterval(5*time.Millisecond)),
+	)
+
+	handler := gin.New()
+	handler.POST("/users", idem.Idempotent(), func(c *gin.Context) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+
+		if c.Query("fail") != "" {
+			c.Status(http.StatusInternalServerError)
+
+			return
+		}
+
+		c.Header("Location", "/users/test")
+		c.JSON(http.StatusCreated, gin.H{"n": n})
+	})
+
+	return handler
+}
+
+func postUser(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	handler := newIdempotentHandler(t, &calls, 0)
+
+	first := postUser(handler, "k1", `{"username":"test"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+
+	// 相同 key 和请求体，返回首次的响应且不再执行 handler
+	retry := postUser(handler, "k1", `{"username":"test"}`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	require.Equal(t, first.Body.String(), retry.Body.String())
+	require.Equal(t, "/users/test", retry.Header().Get("Location"))
+	require.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	require.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 相同 key，不同请求体
+	require.Equal(t, http.StatusUnprocessableEntity, postUser(handler, "k1", `{"username":"other"}`).Code)
+
+	// 没有 key 的请求不受影响
+	require.Equal(t, http.StatusCreated, postUser(handler, "", `{"username":"test"}`).Code)
+	require.Equal(t, http.StatusCreated, postUser(handler, "", `{"username":"test"}`).Code)
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	require.Equal(t, http.StatusBadRequest, postUser(handler, strings.Repeat("k", 256), "").Code)
+}
+
+func TestIdempotentServerErrorNotSaved(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	handler := newIdempotentHandler(t, &calls, 0)
+
+	req := httptest.NewRequest(http.MethodPost, "/users?fail=1", strings.NewReader("{}"))
+	req.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// 5xx 不保存，可以用相同的 key 重试
+	req = httptest.NewRequest(http.MethodPost, "/users?fail=1", strings.NewReader("{}"))
+	req.Header.Set(middleware.IdempotencyKeyHeader, "k1")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotentConcurrent(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	handler := newIdempotentHandler(t, &calls, 50*time.Millisecond)
+
+	const n = 5
+
+	var wg sync.WaitGroup
+
+	results := make([]*httptest.ResponseRecorder, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = postUser(handler, "k1", `{"username":"test"}`)
+		}(i)
+	}
+
+	wg.Wait()
+
+	// 并发的重复请求等待首次请求完成，返回相同的响应
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	for _, w := range results {
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Equal(t, results[0].Body.String(), w.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 未匹配到路由（404）时的 route label，避免将任意路径作为 label 导致基数爆炸.
+const unmatchedRoute = "unmatched"
+
+//nolint:gochecknoglobals
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served by method and route template.",
+	}, []string{"method", "route"})
+)
+
+// 返回 Prometheus 指标中间件，使用 gin 的路由模板（如 /v1/users/:username）作为 label.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		method := c.Request.Method
+		started := time.Now()
+
+		inFlight := httpRequestsInFlight.WithLabelValues(method, route)
+		inFlight.Inc()
+
+		defer func() {
+			inFlight.Dec()
+
+			status := strconv.Itoa(c.Writer.Status())
+			httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+			httpRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(started).Seconds())
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	handler := gin.New()
+	handler.Use(middleware.Metrics())
+	handler.GET("/metrics-test/:name", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/metrics-test/a", "/metrics-test/b", "/metrics-test-missing"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP http_requests_total Total number of HTTP requests by method, route template and status.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/metrics-test/:name",status="204"} 2
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "http_requests_total")
+	require.NoError(t, err)
+}
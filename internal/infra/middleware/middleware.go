@@ -6,21 +6,34 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/service"
 )
 
 const requestIDKey = "X-Request-Id"
 
 // 非全局的中间件集合.
 type Middlewares struct {
-	Audit *AuditMiddleware
+	Audit       *AuditMiddleware
+	Auth        *AuthMiddleware
+	Authz       *AuthzMiddleware
+	RateLimit   *RateLimitMiddleware
+	Idempotency *IdempotencyMiddleware
 }
 
 // 创建非全局的中间件.
-func NewMiddlewares(deps *dependency.Dependency) *Middlewares {
+func NewMiddlewares(deps *dependency.Dependency, svcs *service.Services) *Middlewares {
 	auditMiddleware := NewAuditMiddleware(deps.Logger)
+	authMiddleware := NewAuthMiddleware(deps.Logger, deps.Token)
+	authzMiddleware := NewAuthzMiddleware(deps.Logger, svcs.Authz)
+	rateLimitMiddleware := NewRateLimitMiddleware(deps.Logger, deps.RateLimiter, deps.RateLimits)
+	idempotencyMiddleware := NewIdempotencyMiddleware(deps.Logger, deps.KV, deps.Locker)
 
 	return &Middlewares{
-		Audit: auditMiddleware,
+		Audit:       auditMiddleware,
+		Auth:        authMiddleware,
+		Authz:       authzMiddleware,
+		RateLimit:   rateLimitMiddleware,
+		Idempotency: idempotencyMiddleware,
 	}
 }
 
@@ -41,6 +54,10 @@ func RegisterGlobalMiddleware(handler *gin.Engine) {
 			}),
 			requestid.WithCustomHeaderStrKey(requestIDKey),
 		),
+		// prometheus metrics middleware
+		Metrics(),
+		// opentelemetry tracing middleware，需要在 requestid 之后
+		Tracing(),
 		// logger middleware， 将访问日志也按照规范打到日志中。
 		/*
 			ginlog.SetLogger(ginlog.WithLogger(func(c *gin.Context, out io.Writer, latency time.Duration) zerolog.Logger {
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/ratelimit"
+)
+
+// 限流维度.
+const (
+	RateLimitKeyIP    = "ip"
+	RateLimitKeyUser  = "user"
+	RateLimitKeyRoute = "route"
+)
+
+//nolint:gochecknoglobals
+var httpRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_requests_rate_limited_total",
+	Help: "Total number of HTTP requests rejected by rate limit rule.",
+}, []string{"rule"})
+
+type RateLimitMiddleware struct {
+	l       logger.Logger
+	limiter ratelimit.Limiter
+	rules   *ratelimit.Rules
+}
+
+func NewRateLimitMiddleware(l logger.Logger, limiter ratelimit.Limiter, rules *ratelimit.Rules) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		l:       l,
+		limiter: limiter,
+		rules:   rules,
+	}
+}
+
+// 返回限流中间件，name 引用配置中的规则，每次请求时读取，因此规则修改后无需重启.
+// 规则不存在或限流器出错时放行，超出配额时返回 429.
+func (m *RateLimitMiddleware) Limit(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := m.rules.Get(name)
+		if !ok {
+			c.Next()
+
+			return
+		}
+
+		res, err := m.limiter.Allow(c, name+":"+rateLimitKey(c, rule.Key), rule.Limit)
+		if err != nil {
+			m.l.Ctx(c).Err(err).Errorf("middleware - RateLimit - %s allow failed", name)
+			c.Next()
+
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", ceilSeconds(res.ResetAfter))
+
+		if !res.Allowed {
+			httpRateLimited.WithLabelValues(name).Inc()
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			exception.CodeResponse(c, http.StatusTooManyRequests, "too many requests, please retry later")
+
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// 未登录的请求按 user 限流时退化为按 ip 限流.
+func rateLimitKey(c *gin.Context, key string) string {
+	switch key {
+	case RateLimitKeyRoute:
+		return "route:" + c.FullPath()
+	case RateLimitKeyUser:
+		if p, ok := GetPrincipal(c); ok {
+			return "user:" + p.Username
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// 响应头中的秒数向上取整，避免客户端过早重试.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
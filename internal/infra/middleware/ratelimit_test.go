@@ -0,0 +1,111 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/apis/httpv1"
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	rules := ratelimit.NewRules(map[string]ratelimit.Rule{
+		"login": {Limit: ratelimit.Limit{Rate: 2, Period: time.Minute}, Key: middleware.RateLimitKeyIP},
+	})
+	rl := middleware.NewRateLimitMiddleware(logger.New(logger.Config{Level: "error"}), ratelimit.NewMemory(), rules)
+
+	handler := gin.New()
+	handler.POST("/login", rl.Limit("login"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := do("10.0.0.1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "60", w.Header().Get("X-RateLimit-Reset"))
+
+	require.Equal(t, http.StatusOK, do("10.0.0.1").Code)
+
+	w = do("10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	var resp httpv1.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, http.StatusText(http.StatusTooManyRequests), resp.Code)
+
+	// 按 ip 限流，其他客户端不受影响
+	require.Equal(t, http.StatusOK, do("10.0.0.2").Code)
+
+	// 热加载：放宽规则后立即生效，删除规则后不再限流
+	rules.Store(map[string]ratelimit.Rule{
+		"login": {Limit: ratelimit.Limit{Rate: 10, Period: time.Minute}, Key: middleware.RateLimitKeyIP},
+	})
+	require.Equal(t, http.StatusOK, do("10.0.0.1").Code)
+
+	rules.Store(nil)
+	w = do("10.0.0.1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+
+	rules := ratelimit.NewRules(map[string]ratelimit.Rule{
+		"login": {Limit: ratelimit.Limit{Rate: 1, Period: time.Minute}, Key: middleware.RateLimitKeyIP},
+	})
+	rl := middleware.NewRateLimitMiddleware(logger.New(logger.Config{Level: "error"}), ratelimit.NewMemory(), rules)
+
+	// 与 app 一致，只信任配置的反向代理
+	handler := gin.New()
+	require.NoError(t, handler.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	handler.POST("/login", rl.Limit("login"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	do := func(ip, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	// 非可信代理的客户端伪造 X-Forwarded-For 不会改变限流的 key
+	require.Equal(t, http.StatusOK, do("192.168.0.1", "1.1.1.1"))
+	require.Equal(t, http.StatusTooManyRequests, do("192.168.0.1", "2.2.2.2"))
+
+	// 可信代理转发的请求按 X-Forwarded-For 中的客户端 IP 限流
+	require.Equal(t, http.StatusOK, do("10.0.0.1", "3.3.3.3"))
+	require.Equal(t, http.StatusOK, do("10.0.0.1", "4.4.4.4"))
+	require.Equal(t, http.StatusTooManyRequests, do("10.0.0.2", "3.3.3.3"))
+}
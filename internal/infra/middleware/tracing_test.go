@@ -0,0 +1,56 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ninehills/go-webapp-template/internal/infra/middleware"
+)
+
+func TestTracing(t *testing.T) { //nolint:paralleltest // 修改了全局 TracerProvider
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	handler := gin.New()
+	handler.ContextWithFallback = true
+	handler.Use(requestid.New(), middleware.Tracing())
+	handler.GET("/tracing/:name", func(c *gin.Context) {
+		// 直接使用 *gin.Context 也能取到当前 span
+		require.True(t, trace.SpanContextFromContext(c).IsValid())
+		c.Status(http.StatusOK)
+	})
+
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parent  = "00f067aa0ba902b7"
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/tracing/a", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parent+"-01")
+	req.Header.Set("X-Request-ID", "req-1")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "GET /tracing/:name", spans[0].Name())
+	require.Equal(t, traceID, spans[0].SpanContext().TraceID().String())
+	require.Equal(t, parent, spans[0].Parent().SpanID().String())
+	require.Contains(t, spans[0].Attributes(), attribute.String("http.request_id", "req-1"))
+}
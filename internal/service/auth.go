@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/token"
+)
+
+const (
+	RefreshTokenKeyPrefix = "auth:refresh:"
+	tokenTypeBearer       = "Bearer"
+)
+
+var (
+	errInvalidCredentials = errors.New("invalid username or password")
+	errInvalidRefresh     = errors.New("refresh token is invalid or expired")
+	errUserInactive       = errors.New("user is inactive")
+)
+
+// AuthService 实现了 Auth 接口.
+type AuthService struct {
+	kv    kv.Store
+	token *token.Manager
+	l     logger.Logger
+	svcs  *Services
+}
+
+// New -.
+func NewAuthService(deps *dependency.Dependency, svcs *Services) *AuthService {
+	return &AuthService{
+		kv:    deps.KV,
+		token: deps.Token,
+		l:     deps.Logger,
+		svcs:  svcs,
+	}
+}
+
+// Login - 验证用户密码并签发 Token.
+func (s *AuthService) Login(ctx context.Context, username, pass string) (entity.Token, error) {
+	ok, reason, err := s.svcs.User.AuthenticationPassword(ctx, username, pass)
+	if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - Login - authentication failed: %w", err)
+	}
+
+	if !ok {
+		s.l.Ctx(ctx).Infof("AuthService - Login - user %s rejected: %s", username, reason)
+
+		return entity.Token{}, exception.Unauthorized(errInvalidCredentials)
+	}
+
+	u, err := s.svcs.User.Get(ctx, username)
+	if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - Login - get user failed: %w", err)
+	}
+
+	if !u.IsActive() {
+		return entity.Token{}, exception.Forbidden(errUserInactive)
+	}
+
+	return s.issue(ctx, u.Username)
+}
+
+// Refresh - 轮换 Refresh Token，每个 Refresh Token 只能使用一次.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (entity.Token, error) {
+	key := refreshTokenKey(refreshToken)
+
+	// 只有取到并删除的请求才能完成轮换，避免同一个 Refresh Token 被并发使用
+	username, err := s.kv.Take(ctx, key)
+	if errors.Is(err, kv.ErrNotFound) {
+		return entity.Token{}, exception.Unauthorized(errInvalidRefresh)
+	} else if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - Refresh - take refresh token failed: %w", err)
+	}
+
+	u, err := s.svcs.User.Get(ctx, string(username))
+	if err != nil {
+		if exception.Is(err, exception.NotFound(nil)) {
+			return entity.Token{}, exception.Unauthorized(errInvalidRefresh)
+		}
+
+		return entity.Token{}, fmt.Errorf("- AuthService - Refresh - get user failed: %w", err)
+	}
+
+	if !u.IsActive() {
+		return entity.Token{}, exception.Forbidden(errUserInactive)
+	}
+
+	return s.issue(ctx, u.Username)
+}
+
+// Logout - 吊销 Refresh Token.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	err := s.kv.Del(ctx, refreshTokenKey(refreshToken))
+	if err != nil {
+		return fmt.Errorf("- AuthService - Logout - del refresh token failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) issue(ctx context.Context, username string) (entity.Token, error) {
+	accessToken, _, err := s.token.IssueAccessToken(username)
+	if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - issue - issue access token failed: %w", err)
+	}
+
+	refreshToken, err := s.token.NewRefreshToken()
+	if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - issue - new refresh token failed: %w", err)
+	}
+
+	err = s.kv.Set(ctx, refreshTokenKey(refreshToken), []byte(username), s.token.RefreshTokenTTL())
+	if err != nil {
+		return entity.Token{}, fmt.Errorf("- AuthService - issue - save refresh token failed: %w", err)
+	}
+
+	return entity.Token{
+		AccessToken:  accessToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    int64(s.token.AccessTokenTTL().Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Redis 中只保存 Refresh Token 的摘要，避免存储泄露后被直接使用.
+func refreshTokenKey(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+
+	return RefreshTokenKeyPrefix + hex.EncodeToString(sum[:])
+}
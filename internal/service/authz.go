@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+	"github.com/ninehills/go-webapp-template/pkg/postgres"
+	"github.com/ninehills/go-webapp-template/pkg/sqlite"
+)
+
+const PermissionCacheKeyPrefix = "cache:permission:"
+
+// AuthzService 实现了 Authz 接口.
+type AuthzService struct {
+	tx    *TxManager
+	l     logger.Logger
+	cache cache.Cacher
+	svcs  *Services
+}
+
+// New -.
+func NewAuthzService(deps *dependency.Dependency, svcs *Services) *AuthzService {
+	return &AuthzService{
+		tx:    svcs.txManager(deps),
+		l:     deps.Logger,
+		cache: deps.Cache,
+		svcs:  svcs,
+	}
+}
+
+// Can - 判断是否有权限.
+func (s *AuthzService) Can(ctx context.Context, p entity.Principal, action, resource string) (bool, error) {
+	perms, err := s.Permissions(ctx, p.Username)
+	if err != nil {
+		return false, err
+	}
+
+	isSelf := resource != "" && resource == p.Username
+
+	for _, perm := range perms {
+		if matchPermission(perm, action) {
+			return true, nil
+		}
+
+		if isSelf && strings.HasSuffix(perm, entity.PermissionSelfSuffix) &&
+			matchPermission(strings.TrimSuffix(perm, entity.PermissionSelfSuffix), action) {
+			return true, nil
+		}
+	}
+
+	s.l.Ctx(ctx).Debugf("AuthzService - Can - %s is not allowed to %s %s", p.Username, action, resource)
+
+	return false, nil
+}
+
+// Permissions - 获取用户权限（带 Cache），角色权限变更后最长在缓存过期后生效.
+func (s *AuthzService) Permissions(ctx context.Context, username string) ([]string, error) {
+	var perms []string
+
+	key := PermissionCacheKeyPrefix + username
+
+	err := s.cache.Get(ctx, key, &perms)
+	if err == nil {
+		return perms, nil
+	}
+
+	if !errors.Is(err, cache.ErrMiss) {
+		s.l.Ctx(ctx).Warnf("AuthzService - Permissions - get from cache %s failed: %v", key, err)
+	}
+
+	perms, err = s.db(ctx).ListUserPermissions(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("- AuthzService - Permissions - list failed: %w", err)
+	}
+
+	err = s.cache.Set(ctx, key, perms)
+	if err != nil {
+		s.l.Ctx(ctx).Warnf("AuthzService - Permissions - set to cache %s failed: %v", key, err)
+	}
+
+	return perms, nil
+}
+
+// EnsureRole - 角色不存在时创建，并补充缺失的权限（不会删除已有权限）.
+func (s *AuthzService) EnsureRole(ctx context.Context, in entity.Role) (entity.Role, error) {
+	// 创建之后立即读取，需要读取主库
+	ctx = dao.WithPrimary(ctx)
+
+	_, err := s.db(ctx).GetRole(ctx, in.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = s.db(ctx).CreateRole(ctx, dao.CreateRoleParams{
+			Name:        in.Name,
+			Description: in.Description,
+		})
+		// 多个实例同时启动时可能并发创建，忽略冲突
+		if err != nil && !isDuplicate(err) {
+			return entity.Role{}, fmt.Errorf("- AuthzService - EnsureRole - create failed: %w", err)
+		}
+	} else if err != nil {
+		return entity.Role{}, fmt.Errorf("- AuthzService - EnsureRole - get failed: %w", err)
+	}
+
+	r, err := s.db(ctx).GetRole(ctx, in.Name)
+	if err != nil {
+		return entity.Role{}, fmt.Errorf("- AuthzService - EnsureRole - get failed: %w", err)
+	}
+
+	for _, perm := range in.Permissions {
+		err = s.db(ctx).AddRolePermission(ctx, dao.AddRolePermissionParams{
+			RoleID:     r.ID,
+			Permission: perm,
+		})
+		if err != nil {
+			return entity.Role{}, fmt.Errorf("- AuthzService - EnsureRole - add permission failed: %w", err)
+		}
+	}
+
+	perms, err := s.db(ctx).ListRolePermissions(ctx, r.ID)
+	if err != nil {
+		return entity.Role{}, fmt.Errorf("- AuthzService - EnsureRole - list permissions failed: %w", err)
+	}
+
+	return entity.ToRole(r, perms), nil
+}
+
+// AssignRole - 为用户分配角色.
+// 在事务中调用时不删除权限缓存，由调用方在事务提交之后删除，避免并发请求用提交前的数据回填缓存.
+func (s *AuthzService) AssignRole(ctx context.Context, username, role string) error {
+	// 通常在创建用户之后立即调用，需要读取主库
+	ctx = dao.WithPrimary(ctx)
+
+	u, err := s.db(ctx).GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return exception.NotFound(fmt.Errorf("user %s not found: %w", username, err))
+		}
+
+		return fmt.Errorf("- AuthzService - AssignRole - get user failed: %w", err)
+	}
+
+	r, err := s.db(ctx).GetRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return exception.NotFound(fmt.Errorf("role %s not found: %w", role, err))
+		}
+
+		return fmt.Errorf("- AuthzService - AssignRole - get role failed: %w", err)
+	}
+
+	err = s.db(ctx).AssignUserRole(ctx, dao.AssignUserRoleParams{
+		UserID: u.ID,
+		RoleID: r.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("- AuthzService - AssignRole - assign failed: %w", err)
+	}
+
+	if s.tx.InTx(ctx) {
+		return nil
+	}
+
+	// 删除缓存
+	key := PermissionCacheKeyPrefix + username
+
+	err = s.cache.Del(ctx, key)
+	if err != nil {
+		s.l.Ctx(ctx).Warnf("AuthzService - AssignRole - del cache %s failed: %v", key, err)
+	}
+
+	return nil
+}
+
+// 违反唯一约束，兼容 MySQL、PostgreSQL 和 SQLite.
+func isDuplicate(err error) bool {
+	return mysql.IsDuplicate(err) || postgres.IsDuplicate(err) || sqlite.IsDuplicate(err)
+}
+
+// 判断 perm 是否包含 action，支持 "*" 和 "<resource>:*" 通配.
+func matchPermission(perm, action string) bool {
+	if perm == entity.PermissionAll || perm == action {
+		return true
+	}
+
+	if strings.HasSuffix(perm, ":*") {
+		return strings.HasPrefix(action, strings.TrimSuffix(perm, "*"))
+	}
+
+	return false
+}
+
+// 返回 ctx 中事务的 Querier，不在事务中时返回非事务的 Querier.
+func (s *AuthzService) db(ctx context.Context) dao.Querier {
+	return s.tx.Querier(ctx)
+}
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/mocks"
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+func TestAuthzCan(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	querier := mocks.NewMockQuerier(mockCtl)
+	cacher := mocks.NewMockCacher(mockCtl)
+	authzService := service.NewAuthzService(&dependency.Dependency{
+		DAO:    querier,
+		Logger: logger.New(logger.Config{Level: "error"}),
+		Cache:  cacher,
+	}, &service.Services{})
+
+	perms := map[string][]string{
+		"admin": {entity.PermissionAll},
+		"ops":   {"user:*"},
+		"alice": {entity.PermUserRead + entity.PermissionSelfSuffix, entity.PermUserUpdate + entity.PermissionSelfSuffix},
+	}
+	for username, p := range perms {
+		cacher.EXPECT().Get(gomock.Any(), service.PermissionCacheKeyPrefix+username, gomock.Any()).Return(cache.ErrMiss).AnyTimes()
+		querier.EXPECT().ListUserPermissions(gomock.Any(), username).Return(p, nil).AnyTimes()
+		cacher.EXPECT().Set(gomock.Any(), service.PermissionCacheKeyPrefix+username, gomock.Any()).Return(nil).AnyTimes()
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		action   string
+		resource string
+		allowed  bool
+	}{
+		{name: "admin delete", username: "admin", action: entity.PermUserDelete, resource: "alice", allowed: true},
+		{name: "wildcard resource", username: "ops", action: entity.PermUserDelete, resource: "alice", allowed: true},
+		{name: "read self", username: "alice", action: entity.PermUserRead, resource: "alice", allowed: true},
+		{name: "update self", username: "alice", action: entity.PermUserUpdate, resource: "alice", allowed: true},
+		{name: "read other", username: "alice", action: entity.PermUserRead, resource: "bob", allowed: false},
+		{name: "list", username: "alice", action: entity.PermUserList, resource: "", allowed: false},
+		{name: "delete self", username: "alice", action: entity.PermUserDelete, resource: "alice", allowed: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			allowed, err := authzService.Can(context.Background(), entity.Principal{Username: tc.username}, tc.action, tc.resource)
+			require.NoError(t, err)
+			require.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+func TestAuthzAssignRoleInTx(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	querier := mocks.NewMockQuerier(mockCtl)
+	cacher := mocks.NewMockCacher(mockCtl)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	l := logger.New(logger.Config{Level: "error"})
+	tx := service.NewTxManager(db, querier, l, service.TxQuerier(func(dao.DBTX) dao.Querier { return querier }))
+	authzService := service.NewAuthzService(&dependency.Dependency{
+		DAO:    querier,
+		Logger: l,
+		Cache:  cacher,
+	}, &service.Services{Tx: tx})
+
+	ctx := context.Background()
+
+	querier.EXPECT().GetUser(gomock.Any(), "alice").Return(dao.User{ID: 1, Username: "alice"}, nil).Times(2)
+	querier.EXPECT().GetRole(gomock.Any(), entity.RoleUser).Return(dao.Role{ID: 2, Name: entity.RoleUser}, nil).Times(2)
+	querier.EXPECT().AssignUserRole(gomock.Any(), dao.AssignUserRoleParams{UserID: 1, RoleID: 2}).Return(nil).Times(2)
+
+	// 事务中不删除权限缓存，由调用方在提交之后删除
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = tx.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		return authzService.AssignRole(ctx, "alice", entity.RoleUser)
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// 不在事务中时直接删除权限缓存
+	cacher.EXPECT().Del(gomock.Any(), service.PermissionCacheKeyPrefix+"alice").Return(nil)
+	require.NoError(t, authzService.AssignRole(ctx, "alice", entity.RoleUser))
+}
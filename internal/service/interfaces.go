@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ninehills/go-webapp-template/internal/entity"
 	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
@@ -10,17 +11,46 @@ import (
 
 // 定义 Service 聚合结构.
 type Services struct {
-	User User
+	User  User
+	Auth  Auth
+	Authz Authz
+	// 各 Service 共享的事务管理，跨 Service 的调用通过 ctx 加入同一个事务
+	Tx *TxManager
 }
 
 // 创建所有 Service，另外将srvs 注入到各个 Service 中，方便相互之间的引用.
 func NewServices(deps *dependency.Dependency) *Services {
-	svcs := &Services{}
+	opts := []TxOption{TxQuerier(deps.Querier)}
+
+	// 隔离级别和死锁重试只对 MySQL / PostgreSQL 生效，隔离级别已在加载配置时校验
+	switch {
+	case deps.MySQL != nil:
+		isolation, _ := ParseTxIsolation(deps.Config.MySQL.TxIsolation)
+		opts = append(opts, TxIsolation(isolation), TxMaxRetries(deps.Config.MySQL.TxMaxRetries))
+	case deps.Postgres != nil:
+		isolation, _ := ParseTxIsolation(deps.Config.Postgres.TxIsolation)
+		opts = append(opts, TxIsolation(isolation), TxMaxRetries(deps.Config.Postgres.TxMaxRetries))
+	}
+
+	svcs := &Services{
+		Tx: NewTxManager(deps.DB, deps.DAO, deps.Logger, opts...),
+	}
 	svcs.User = NewUserService(deps, svcs)
+	svcs.Auth = NewAuthService(deps, svcs)
+	svcs.Authz = NewAuthzService(deps, svcs)
 
 	return svcs
 }
 
+// 未设置 Tx 时（如单元测试）使用不开启事务的 TxManager.
+func (s *Services) txManager(deps *dependency.Dependency) *TxManager {
+	if s.Tx == nil {
+		s.Tx = NewTxManager(nil, deps.DAO, deps.Logger)
+	}
+
+	return s.Tx
+}
+
 type (
 	// User Interface.
 	User interface {
@@ -30,14 +60,42 @@ type (
 		Get(ctx context.Context, username string) (entity.User, error)
 		// 根据用户名称获取用户（带 Cache）
 		CacheGet(ctx context.Context, username string) (entity.User, error)
-		// 更新用户
+		// 更新用户，in.Version 不为 0 时要求与当前版本一致，否则返回 PreconditionFailed
 		Update(ctx context.Context, in entity.User) (entity.User, error)
-		// 删除用户
+		// 部分更新用户，只修改 patch 中不为 nil 的字段，可以将字段更新为空值
+		Patch(ctx context.Context, in entity.UserPatch) (entity.User, error)
+		// 软删除用户
 		Delete(ctx context.Context, username string) error
+		// 恢复已软删除的用户
+		Restore(ctx context.Context, username string) (entity.User, error)
+		// 永久删除 before 之前软删除的用户，返回删除的用户数
+		Purge(ctx context.Context, before time.Time) (int64, error)
 		// 分页查询用户信息，支持丰富的查询条件
 		Query(ctx context.Context, p entity.PageQuery, o entity.OrderQuery, u entity.UserQuery) (
 			entity.PageResult, []entity.User, error)
 		// 验证密码是否正确
 		AuthenticationPassword(ctx context.Context, username, password string) (ok bool, reason string, err error)
 	}
+
+	// Auth Interface.
+	Auth interface {
+		// 验证用户名密码，签发 Access Token 和 Refresh Token
+		Login(ctx context.Context, username, password string) (entity.Token, error)
+		// 使用 Refresh Token 换取新的 Token，旧的 Refresh Token 立即失效
+		Refresh(ctx context.Context, refreshToken string) (entity.Token, error)
+		// 吊销 Refresh Token，操作是幂等的
+		Logout(ctx context.Context, refreshToken string) error
+	}
+
+	// Authz Interface.
+	Authz interface {
+		// 判断 principal 能否对 resource 执行 action，resource 为空代表不针对具体对象
+		Can(ctx context.Context, principal entity.Principal, action, resource string) (bool, error)
+		// 获取用户通过角色拥有的全部权限
+		Permissions(ctx context.Context, username string) ([]string, error)
+		// 创建角色并补充权限，操作是幂等的
+		EnsureRole(ctx context.Context, role entity.Role) (entity.Role, error)
+		// 为用户分配角色，操作是幂等的
+		AssignRole(ctx context.Context, username, role string) error
+	}
 )
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/config"
+	daosqlite "github.com/ninehills/go-webapp-template/internal/dao/sqlite"
+	"github.com/ninehills/go-webapp-template/internal/entity"
+	"github.com/ninehills/go-webapp-template/internal/infra/dependency"
+	"github.com/ninehills/go-webapp-template/internal/infra/exception"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/migrate"
+	"github.com/ninehills/go-webapp-template/pkg/sqlite"
+	"github.com/ninehills/go-webapp-template/pkg/token"
+	"github.com/ninehills/go-webapp-template/sql/sqlite/migrations"
+)
+
+// 使用临时文件的 SQLite 数据库创建 Service，不依赖外部服务（与未配置 Redis 时一样使用进程内的缓存和 KV）.
+func newSQLiteServices(t *testing.T) *service.Services {
+	t.Helper()
+
+	l := logger.New(logger.Config{Format: "text", Level: "error"})
+
+	db, err := sqlite.New(l, filepath.Join(t.TempDir(), "app.db"))
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	m, err := migrate.New(db.DB, l, migrations.FS, migrate.Dialect(migrate.DialectSQLite))
+	require.NoError(t, err)
+	require.NoError(t, m.Up(context.Background()))
+
+	cfg := &config.Config{}
+	cfg.Database.Driver = "sqlite"
+
+	svcs := service.NewServices(&dependency.Dependency{
+		Config: cfg,
+		Logger: l,
+		DB:     db.DB,
+		SQLite: db,
+		DAO:    daosqlite.NewQuerier(db.DB),
+		Cache:  cache.NewMemory(100, time.Minute),
+		Token:  token.New("secret"),
+		KV:     kv.NewMemory(),
+	})
+
+	for _, r := range entity.DefaultRoles() {
+		_, err := svcs.Authz.EnsureRole(context.Background(), r)
+		require.NoError(t, err)
+	}
+
+	return svcs
+}
+
+func TestUserSQLite(t *testing.T) {
+	t.Parallel()
+
+	svcs := newSQLiteServices(t)
+	ctx := context.Background()
+
+	alice, err := svcs.User.Create(ctx, entity.User{
+		Username: "alice", Email: "alice@example.com", Password: "pass", Status: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), alice.Version)
+	require.WithinDuration(t, time.Now(), alice.CreatedAt, time.Minute)
+
+	// 创建用户时在同一个事务中分配了默认角色
+	ok, err := svcs.Authz.Can(ctx, entity.Principal{Username: "alice"}, entity.PermUserRead, "alice")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// 邮箱冲突返回具体的字段
+	_, err = svcs.User.Create(ctx, entity.User{Username: "bob", Email: "alice@example.com", Password: "pass"})
+
+	var e *exception.Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, http.StatusConflict, e.Code())
+	require.Equal(t, "email", e.Field())
+
+	_, err = svcs.User.Create(ctx, entity.User{Username: "bob", Email: "bob@example.com", Password: "pass", Status: 2})
+	require.NoError(t, err)
+
+	// 乐观锁
+	description := "updated"
+
+	u, err := svcs.User.Patch(ctx, entity.UserPatch{Username: "alice", Description: &description, Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, "updated", u.Description)
+	require.Equal(t, int64(2), u.Version)
+
+	_, err = svcs.User.Patch(ctx, entity.UserPatch{Username: "alice", Description: &description, Version: 1})
+	require.True(t, exception.Is(err, exception.PreconditionFailed(nil))) //nolint:testifylint
+
+	// QueryUser 在 SQLite 上同样可用
+	page := entity.PageQuery{PageNo: 1, PageSize: 10}
+
+	res, users, err := svcs.User.Query(ctx, page, entity.OrderQuery{OrderBy: "username", Order: "desc"}, entity.UserQuery{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.TotalCount)
+	require.Equal(t, "bob", users[0].Username)
+
+	res, users, err = svcs.User.Query(ctx, page, entity.OrderQuery{}, entity.UserQuery{Status: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.TotalCount)
+	require.Equal(t, "bob", users[0].Username)
+
+	// 软删除、恢复和永久删除
+	require.NoError(t, svcs.User.Delete(ctx, "bob"))
+
+	_, err = svcs.User.Get(ctx, "bob")
+	require.True(t, exception.Is(err, exception.NotFound(nil))) //nolint:testifylint
+
+	res, _, err = svcs.User.Query(ctx, page, entity.OrderQuery{}, entity.UserQuery{IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), res.TotalCount)
+
+	u, err = svcs.User.Restore(ctx, "bob")
+	require.NoError(t, err)
+	require.Nil(t, u.DeletedAt)
+
+	require.NoError(t, svcs.User.Delete(ctx, "bob"))
+
+	n, err := svcs.User.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = svcs.User.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	_, err = svcs.User.Restore(ctx, "bob")
+	require.True(t, exception.Is(err, exception.NotFound(nil))) //nolint:testifylint
+}
+
+func TestAuthSQLite(t *testing.T) {
+	t.Parallel()
+
+	svcs := newSQLiteServices(t)
+	ctx := context.Background()
+
+	_, err := svcs.User.Create(ctx, entity.User{
+		Username: "alice", Email: "alice@example.com", Password: "pass", Status: 1,
+	})
+	require.NoError(t, err)
+
+	tk, err := svcs.Auth.Login(ctx, "alice", "pass")
+	require.NoError(t, err)
+	require.NotEmpty(t, tk.RefreshToken)
+
+	// Refresh Token 只能使用一次
+	rotated, err := svcs.Auth.Refresh(ctx, tk.RefreshToken)
+	require.NoError(t, err)
+	require.NotEqual(t, tk.RefreshToken, rotated.RefreshToken)
+
+	_, err = svcs.Auth.Refresh(ctx, tk.RefreshToken)
+	require.True(t, exception.Is(err, exception.Unauthorized(nil))) //nolint:testifylint
+
+	require.NoError(t, svcs.Auth.Logout(ctx, rotated.RefreshToken))
+
+	_, err = svcs.Auth.Refresh(ctx, rotated.RefreshToken)
+	require.True(t, exception.Is(err, exception.Unauthorized(nil))) //nolint:testifylint
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+	"github.com/ninehills/go-webapp-template/pkg/postgres"
+)
+
+const (
+	defaultTxMaxRetries = 3
+	defaultTxBackoff    = 20 * time.Millisecond
+)
+
+type ctxKeyTx struct{}
+
+// ctx 中正在进行的事务，depth 为嵌套层数，用于生成 savepoint 名称.
+type txState struct {
+	tx    *sql.Tx
+	q     dao.Querier
+	depth int
+}
+
+// TxManager 在一个事务中执行多个 DAO 调用，事务通过 ctx 传递.
+type TxManager struct {
+	db         *sql.DB
+	q          dao.Querier
+	l          logger.Logger
+	isolation  sql.IsolationLevel
+	maxRetries int
+	backoff    time.Duration
+	newQuerier func(db dao.DBTX) dao.Querier
+}
+
+// TxOption -.
+type TxOption func(*TxManager)
+
+// TxIsolation - 事务隔离级别，默认使用数据库的默认值.
+func TxIsolation(level sql.IsolationLevel) TxOption {
+	return func(m *TxManager) {
+		m.isolation = level
+	}
+}
+
+// TxMaxRetries - 死锁或锁等待超时时的最大重试次数，0 表示不重试.
+func TxMaxRetries(n int) TxOption {
+	return func(m *TxManager) {
+		m.maxRetries = n
+	}
+}
+
+// TxBackoff - 第 n 次重试前等待 n * backoff.
+func TxBackoff(d time.Duration) TxOption {
+	return func(m *TxManager) {
+		m.backoff = d
+	}
+}
+
+// TxQuerier - 基于事务创建 Querier，默认使用 MySQL 方言的 dao.New.
+func TxQuerier(newQuerier func(db dao.DBTX) dao.Querier) TxOption {
+	return func(m *TxManager) {
+		m.newQuerier = newQuerier
+	}
+}
+
+// New -.
+// db 为 nil 时（如单元测试中使用 mock 的 Querier）不开启事务，直接使用 q 执行.
+func NewTxManager(db *sql.DB, q dao.Querier, l logger.Logger, opts ...TxOption) *TxManager {
+	m := &TxManager{
+		db:         db,
+		q:          q,
+		l:          l,
+		isolation:  sql.LevelDefault,
+		maxRetries: defaultTxMaxRetries,
+		backoff:    defaultTxBackoff,
+		newQuerier: func(db dao.DBTX) dao.Querier { return dao.New(db) },
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// 解析配置中的隔离级别名称，为空时使用数据库的默认值.
+func ParseTxIsolation(name string) (sql.IsolationLevel, error) {
+	switch name {
+	case "":
+		return sql.LevelDefault, nil
+	case "read-uncommitted":
+		return sql.LevelReadUncommitted, nil
+	case "read-committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable-read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+
+	return sql.LevelDefault, fmt.Errorf("unknown tx isolation %q", name)
+}
+
+// Querier - 返回 ctx 中事务的 Querier，不在事务中时返回非事务的 Querier.
+func (m *TxManager) Querier(ctx context.Context) dao.Querier {
+	if st, ok := ctx.Value(ctxKeyTx{}).(*txState); ok {
+		return st.q
+	}
+
+	return m.q
+}
+
+// InTx - ctx 中是否存在事务.
+func (m *TxManager) InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(ctxKeyTx{}).(*txState)
+
+	return ok
+}
+
+// WithinTx - 在事务中执行 fn，fn 返回 error 或 panic 时回滚，否则提交.
+// fn 中的 DAO 调用需要使用传入的 q，或者将传入的 ctx 继续传递给其他 Service.
+// ctx 中已经存在事务时使用 savepoint 嵌套，只回滚 fn 中的修改.
+// 最外层事务遇到死锁、序列化冲突或锁等待超时时会重新执行 fn，因此 fn 中不应有事务之外的副作用（如删除缓存），
+// 这类操作应在 WithinTx 返回之后执行.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context, q dao.Querier) error) error {
+	if st, ok := ctx.Value(ctxKeyTx{}).(*txState); ok {
+		return m.savepoint(ctx, st, fn)
+	}
+
+	if m.db == nil {
+		return fn(ctx, m.q)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || attempt > m.maxRetries || !isRetryable(err) {
+			return err
+		}
+
+		m.l.Ctx(ctx).Warnf("TxManager - WithinTx - attempt %d failed, retrying: %v", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("- TxManager - WithinTx - retry canceled: %w", err)
+		case <-time.After(time.Duration(attempt) * m.backoff):
+		}
+	}
+}
+
+func isRetryable(err error) bool {
+	return mysql.IsRetryable(err) || postgres.IsRetryable(err)
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context, q dao.Querier) error) (err error) {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: m.isolation})
+	if err != nil {
+		return fmt.Errorf("- TxManager - WithinTx - begin failed: %w", err)
+	}
+
+	st := &txState{tx: tx, q: m.newQuerier(tx)}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+
+			panic(p)
+		}
+
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				m.l.Ctx(ctx).Warnf("TxManager - WithinTx - rollback failed: %v", rbErr)
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, ctxKeyTx{}, st), st.q); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("- TxManager - WithinTx - commit failed: %w", err)
+	}
+
+	return nil
+}
+
+func (m *TxManager) savepoint(
+	ctx context.Context, parent *txState, fn func(ctx context.Context, q dao.Querier) error,
+) (err error) {
+	st := &txState{tx: parent.tx, q: parent.q, depth: parent.depth + 1}
+	name := fmt.Sprintf("sp_%d", st.depth)
+
+	if _, err = st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("- TxManager - WithinTx - savepoint failed: %w", err)
+	}
+
+	defer func() {
+		p := recover()
+		if p == nil && err == nil {
+			return
+		}
+
+		// 死锁时整个事务已被回滚，savepoint 不再存在，返回原始错误由最外层重试
+		if _, rbErr := st.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			m.l.Ctx(ctx).Warnf("TxManager - WithinTx - rollback to savepoint %s failed: %v", name, rbErr)
+		}
+
+		if p != nil {
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, ctxKeyTx{}, st), st.q); err != nil {
+		return err
+	}
+
+	if _, err = st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("- TxManager - WithinTx - release savepoint failed: %w", err)
+	}
+
+	return nil
+}
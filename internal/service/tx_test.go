@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/internal/dao"
+	"github.com/ninehills/go-webapp-template/internal/service"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+func newTxManager(t *testing.T, opts ...service.TxOption) (*service.TxManager, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	opts = append([]service.TxOption{service.TxBackoff(time.Millisecond)}, opts...)
+
+	return service.NewTxManager(db, dao.New(db), logger.New(logger.Config{Level: "error"}), opts...), mock
+}
+
+func TestTxManagerCommitRollback(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newTxManager(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err := m.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+		// 事务中通过 ctx 获取的是同一个 Querier
+		require.Same(t, q, m.Querier(ctx))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = m.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+		return errInternal
+	})
+	require.ErrorIs(t, err, errInternal)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	require.Panics(t, func() {
+		_ = m.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+			panic("boom")
+		})
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManagerSavepoint(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newTxManager(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		// 内层失败只回滚内层的修改，外层事务仍然提交
+		err := m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+			return m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+				return nil
+			})
+		})
+		if err == nil {
+			return errInternal
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		require.ErrorIs(t, m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+			return errInternal
+		}), errInternal)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxManagerRetry(t *testing.T) {
+	t.Parallel()
+
+	m, mock := newTxManager(t, service.TxMaxRetries(2), service.TxIsolation(sql.LevelReadCommitted))
+	ctx := context.Background()
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+
+	// 死锁时重新执行整个事务
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	attempts := 0
+
+	err := m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		attempts++
+		if attempts == 1 {
+			return deadlock
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	// 超过重试次数后返回最后一次的错误
+	attempts = 0
+
+	for i := 0; i < 3; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	err = m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		attempts++
+
+		return &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+	})
+	require.Error(t, err)
+	require.Equal(t, 3, attempts)
+
+	// 其他错误不重试
+	attempts = 0
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = m.WithinTx(ctx, func(ctx context.Context, _ dao.Querier) error {
+		attempts++
+
+		return errInternal
+	})
+	require.ErrorIs(t, err, errInternal)
+	require.Equal(t, 1, attempts)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
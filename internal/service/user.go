@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ninehills/go-webapp-template/internal/dao"
 	"github.com/ninehills/go-webapp-template/internal/entity"
@@ -16,23 +19,95 @@ import (
 	"github.com/ninehills/go-webapp-template/pkg/password"
 )
 
-const UserCacheKeyPrefix = "cache:user:"
+const (
+	UserCacheKeyPrefix = "cache:user:"
+	// 分页查询结果的缓存 key 前缀，实际 key 还会带上 UsersCacheTag 的代际
+	UserQueryCacheKeyPrefix = "cache:users:query:"
+	// 用户列表相关缓存的标签，用户增删改时失效
+	UsersCacheTag = "users"
+
+	userQueryCacheTTL = time.Minute
+
+	userCacheNegativeTTL = 30 * time.Second
+	userCacheStaleTTL    = 30 * time.Second
+	userCacheJitter      = 0.1
+)
 
 // UserService 实现了 User 接口.
 type UserService struct {
-	db    dao.Querier
-	l     logger.Logger
-	cache cache.Cacher
-	svcs  *Services
+	tx      *TxManager
+	l       logger.Logger
+	cache   cache.Cacher
+	users   *cache.Typed[cachedUser]
+	queries *cache.Typed[userQueryResult]
+	tags    *cache.Tags
+	svcs    *Services
+}
+
+// 缓存中的 User，不包含密码：gob 会编码全部导出字段，不受 json:"-" 影响.
+type cachedUser struct {
+	ID          int64
+	Username    string
+	Status      int32
+	Email       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+	Version     int64
+}
+
+func toCachedUser(u entity.User) cachedUser {
+	return cachedUser{
+		ID:          u.ID,
+		Username:    u.Username,
+		Status:      u.Status,
+		Email:       u.Email,
+		Description: u.Description,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		DeletedAt:   u.DeletedAt,
+		Version:     u.Version,
+	}
+}
+
+func (u cachedUser) user() entity.User {
+	return entity.User{
+		ID:          u.ID,
+		Username:    u.Username,
+		Status:      u.Status,
+		Email:       u.Email,
+		Description: u.Description,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		DeletedAt:   u.DeletedAt,
+		Version:     u.Version,
+	}
+}
+
+// 缓存的分页查询结果.
+type userQueryResult struct {
+	Page  entity.PageResult
+	Users []cachedUser
 }
 
 // New -.
 func NewUserService(deps *dependency.Dependency, svcs *Services) *UserService {
 	return &UserService{
-		db:    deps.DAO,
+		tx:    svcs.txManager(deps),
 		l:     deps.Logger,
 		cache: deps.Cache,
-		svcs:  svcs,
+		// gob 保留时间的时区
+		users: cache.NewTyped[cachedUser](deps.Cache, cache.Gob, cache.DefaultReadThroughTTL,
+			cache.Jitter(userCacheJitter),
+			cache.StaleTTL(userCacheStaleTTL),
+			cache.Negative(userCacheNegativeTTL, isNotFound),
+		),
+		queries: cache.NewTyped[userQueryResult](deps.Cache, cache.Gob, userQueryCacheTTL,
+			cache.Jitter(userCacheJitter),
+		),
+		tags: cache.NewTags(deps.Cache, cache.DefaultTagTTL),
+		svcs: svcs,
 	}
 }
 
@@ -51,27 +126,46 @@ func (s *UserService) Create(ctx context.Context, in entity.User) (entity.User,
 		Password:    encryptPassword,
 	}
 
-	err = s.db.CreateUser(ctx, arg)
-	if err != nil {
-		// Ugly hack to handle duplicate key error, only for mysql.
-		if strings.Contains(err.Error(), "Duplicate entry") {
-			return entity.User{}, exception.Conflict(fmt.Errorf("duplicate name or user id"))
+	// 创建用户和分配角色在同一个事务中，避免出现没有角色的用户
+	var u dao.User
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+		err := q.CreateUser(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("- UserService - Create - create failed: %w", err)
 		}
 
-		return entity.User{}, fmt.Errorf("- UserService - Create - create failed: %w", err)
-	}
+		// 新用户默认分配普通用户角色
+		err = s.svcs.Authz.AssignRole(ctx, in.Username, entity.RoleUser)
+		if err != nil {
+			return fmt.Errorf("- UserService - Create - assign role failed: %w", err)
+		}
 
-	u, err := s.db.GetUser(ctx, in.Username)
+		u, err = q.GetUser(ctx, in.Username)
+		if err != nil {
+			return fmt.Errorf("- UserService - Create - get failed: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return entity.User{}, fmt.Errorf("- UserService - Create - get failed: %w", err)
+		// 用户名或邮箱冲突，以及重试之后仍然失败的死锁等错误
+		if e := exception.FromDB(err); e != nil {
+			return entity.User{}, e
+		}
+
+		return entity.User{}, err
 	}
 
+	// 删除可能存在的负缓存和事务中分配角色之前的权限缓存，并使列表缓存失效
+	s.invalidate(ctx, UserCacheKeyPrefix+in.Username, PermissionCacheKeyPrefix+in.Username)
+
 	return entity.ToUser(u), nil
 }
 
 // Get - 根据 User ID 获取 User.
 func (s *UserService) Get(ctx context.Context, username string) (entity.User, error) {
-	u, err := s.db.GetUser(ctx, username)
+	u, err := s.db(ctx).GetUser(ctx, username)
 	s.l.Ctx(ctx).Debugf("UserService - Get - username: %s, error[%v]", username, err)
 
 	if err != nil {
@@ -85,46 +179,68 @@ func (s *UserService) Get(ctx context.Context, username string) (entity.User, er
 	return entity.ToUser(u), nil
 }
 
-// CacheGet -.
+// CacheGet - 读穿透缓存获取 User，不存在的用户也会被短暂缓存.
 func (s *UserService) CacheGet(ctx context.Context, username string) (entity.User, error) {
-	var user entity.User
-
 	key := UserCacheKeyPrefix + username
 
-	err := s.cache.Get(ctx, key, &user)
-	if err == nil {
-		s.l.Ctx(ctx).Debugf("UserService - GetCache - cache hit %s: %+v", key, user)
+	user, err := s.users.Load(ctx, key, func(ctx context.Context) (cachedUser, error) {
+		s.l.Ctx(ctx).Debugf("UserService - CacheGet - cache miss %s", key)
 
-		return user, nil
-	}
+		// 写入之后缓存被删除，重新加载时读取主库，避免把副本上的旧数据缓存一个 TTL
+		u, err := s.Get(dao.WithPrimary(ctx), username)
 
-	if errors.Is(err, cache.ErrMiss) {
-		s.l.Ctx(ctx).Debugf("UserService - GetCache - cache miss %s", key)
-	} else {
-		s.l.Ctx(ctx).Warnf("UserService - GetCache - get from cache %s failed: %v", key, err)
+		return toCachedUser(u), err
+	})
+	if errors.Is(err, cache.ErrNegative) {
+		return entity.User{}, exception.NotFound(fmt.Errorf("user %s not found: %w", username, err))
+	} else if err != nil {
+		return entity.User{}, err
 	}
 
-	u, err := s.Get(ctx, username)
+	return user.user(), nil
+}
+
+// QueryUser - 分页查询 User 信息，结果按查询条件缓存，用户增删改时全部失效.
+func (s *UserService) Query(
+	ctx context.Context, p entity.PageQuery, o entity.OrderQuery, u entity.UserQuery) (
+	entity.PageResult, []entity.User, error,
+) {
+	key, err := s.tags.Key(ctx, userQueryCacheKey(p, o, u), UsersCacheTag)
 	if err != nil {
-		return entity.User{}, err
+		s.l.Ctx(ctx).Warnf("UserService - Query - get cache tag failed, query without cache: %v", err)
+
+		return s.query(ctx, p, o, u)
 	}
 
-	err = s.cache.Set(ctx, key, u)
+	r, err := s.queries.Load(ctx, key, func(ctx context.Context) (userQueryResult, error) {
+		// 同 CacheGet，重新加载时读取主库
+		page, users, err := s.query(dao.WithPrimary(ctx), p, o, u)
+
+		r := userQueryResult{Page: page, Users: make([]cachedUser, len(users))}
+		for i, user := range users {
+			r.Users[i] = toCachedUser(user)
+		}
+
+		return r, err
+	})
 	if err != nil {
-		s.l.Ctx(ctx).Warnf("UserService - GetCache - set to cache %s/%+v failed: %v", key, u, err)
+		return entity.PageResult{}, nil, err
 	}
 
-	s.l.Ctx(ctx).Debugf("UserService - GetCache - set cache %s/%+v success", key, u)
+	// gob 不区分空切片和 nil，始终返回 [] 而不是 null
+	users := make([]entity.User, len(r.Users))
+	for i, user := range r.Users {
+		users[i] = user.user()
+	}
 
-	return u, nil
+	return r.Page, users, nil
 }
 
-// QueryUser - 分页查询 User 信息.
-func (s *UserService) Query(
+func (s *UserService) query(
 	ctx context.Context, p entity.PageQuery, o entity.OrderQuery, u entity.UserQuery) (
 	entity.PageResult, []entity.User, error,
 ) {
-	us, count, err := s.db.QueryUser(ctx, dao.QueryUserParams{
+	us, count, err := s.db(ctx).QueryUser(ctx, dao.QueryUserParams{
 		Offset:   (p.PageNo - 1) * p.PageSize,
 		Limit:    p.PageSize,
 		Order:    o.Order,
@@ -132,8 +248,14 @@ func (s *UserService) Query(
 		Username: u.Username,
 		Status:   u.Status,
 		Email:    u.Email,
+		// 是否包含已删除的用户由 Controller 做权限校验
+		IncludeDeleted: u.IncludeDeleted,
 	})
 	if err != nil {
+		if errors.Is(err, dao.ErrUnsortable) || errors.Is(err, dao.ErrInvalidOrder) {
+			return entity.PageResult{}, nil, exception.BadRequest(err)
+		}
+
 		return entity.PageResult{}, nil, fmt.Errorf("- UserService - ListWithPages - list failed: %w", err)
 	}
 
@@ -149,60 +271,136 @@ func (s *UserService) Query(
 	}, users, nil
 }
 
-// Update - 更新 User.
+// 分页查询的缓存 key，对查询条件归一化后取 sha256，等价的查询使用同一个 key.
+func userQueryCacheKey(p entity.PageQuery, o entity.OrderQuery, u entity.UserQuery) string {
+	order := strings.ToLower(o.Order)
+	if order == "" {
+		order = "asc"
+	}
+
+	orderBy := o.OrderBy
+	if orderBy == "" {
+		orderBy = "id"
+	}
+
+	normalized := fmt.Sprintf("%d|%d|%s|%s|%q|%d|%q|%t",
+		p.PageNo, p.PageSize, order, orderBy, u.Username, u.Status, u.Email, u.IncludeDeleted)
+	sum := sha256.Sum256([]byte(normalized))
+
+	return UserQueryCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// Update - 更新 User，空字符串和 0 代表不修改，需要清空字段时使用 Patch.
 func (s *UserService) Update(ctx context.Context, in entity.User) (entity.User, error) {
-	// check if User exists
-	_, err := s.db.GetUser(ctx, in.Username)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return entity.User{}, exception.NotFound(fmt.Errorf("user %s not found", in.Username))
-		}
+	patch := entity.UserPatch{
+		Username: in.Username,
+		Version:  in.Version,
+	}
 
-		return entity.User{}, fmt.Errorf("- UserService - Update - get failed: %w", err)
+	if in.Status != 0 {
+		patch.Status = &in.Status
 	}
 
+	if in.Email != "" {
+		patch.Email = &in.Email
+	}
+
+	if in.Description != "" {
+		patch.Description = &in.Description
+	}
+
+	if in.Password != "" {
+		patch.Password = &in.Password
+	}
+
+	return s.Patch(ctx, patch)
+}
+
+// Patch - 部分更新 User.
+func (s *UserService) Patch(ctx context.Context, in entity.UserPatch) (entity.User, error) {
 	arg := dao.UpdateUserParams{
 		Username:    in.Username,
-		Status:      sql.NullInt32{Int32: in.Status, Valid: in.Status != 0},
-		Email:       sql.NullString{String: in.Email, Valid: in.Email != ""},
-		Description: sql.NullString{String: in.Description, Valid: in.Description != ""},
+		Email:       nullString(in.Email),
+		Description: nullString(in.Description),
 	}
 
-	if in.Password != "" {
-		encryptPassword, err := password.EncryptPassword(in.Password)
+	if in.Status != nil {
+		arg.Status = sql.NullInt32{Int32: *in.Status, Valid: true}
+	}
+
+	// 在事务之外计算密码哈希，避免长时间持有事务
+	if in.Password != nil {
+		encryptPassword, err := password.EncryptPassword(*in.Password)
 		if err != nil {
-			return entity.User{}, fmt.Errorf("- UserService - Update - encrypt password failed: %w", err)
+			return entity.User{}, fmt.Errorf("- UserService - Patch - encrypt password failed: %w", err)
 		}
 
 		arg.Password = sql.NullString{String: encryptPassword, Valid: true}
 	}
 
-	err = s.db.UpdateUser(ctx, arg)
-	if err != nil {
-		return entity.User{}, fmt.Errorf("- UserService - Update - update failed: %w", err)
-	}
+	var u dao.User
 
-	// 先更新后查询
-	u, err := s.db.GetUser(ctx, in.Username)
-	if err != nil {
-		return entity.User{}, fmt.Errorf("- UserService - Update - get failed: %w", err)
-	}
+	// 先读取版本再更新，更新后再读取，都需要读取主库；在事务中时本身就使用主库
+	err := s.tx.WithinTx(dao.WithPrimary(ctx), func(ctx context.Context, q dao.Querier) error {
+		// check if User exists
+		cur, err := q.GetUser(ctx, in.Username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return exception.NotFound(fmt.Errorf("user %s not found", in.Username))
+			}
 
-	// 删除缓存
-	key := UserCacheKeyPrefix + in.Username
+			return fmt.Errorf("- UserService - Patch - get failed: %w", err)
+		}
+
+		// 未指定版本时以刚读取的版本为条件，同样可以防止读取之后的并发修改被覆盖
+		arg.Version = in.Version
+		if arg.Version == 0 {
+			arg.Version = cur.Version
+		}
+
+		if arg.Version != cur.Version {
+			return exception.PreconditionFailed(
+				fmt.Errorf("user %s has been modified, current version is %d", in.Username, cur.Version))
+		}
+
+		n, err := q.UpdateUser(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("- UserService - Patch - update failed: %w", err)
+		}
+
+		if n == 0 {
+			return exception.PreconditionFailed(
+				fmt.Errorf("user %s has been modified concurrently", in.Username))
+		}
+
+		// 先更新后查询
+		u, err = q.GetUser(ctx, in.Username)
+		if err != nil {
+			return fmt.Errorf("- UserService - Patch - get failed: %w", err)
+		}
 
-	err = s.cache.Del(ctx, key)
+		return nil
+	})
 	if err != nil {
-		s.l.Ctx(ctx).Warnf("UserService - Update - del cache %s failed: %v", key, err)
+		// 修改后的邮箱与其他用户冲突，以及重试之后仍然失败的死锁等错误
+		if e := exception.FromDB(err); e != nil {
+			return entity.User{}, e
+		}
+
+		return entity.User{}, err
 	}
 
+	// 删除缓存
+	s.invalidate(ctx, UserCacheKeyPrefix+in.Username)
+
 	return entity.ToUser(u), nil
 }
 
-// Delete - 删除 User，操作是幂等的，也就是如果 User 不存在时返回成功.
+// Delete - 软删除 User，操作是幂等的，也就是如果 User 不存在时返回成功.
+// 用户名在永久删除（Purge）之前仍然被占用，角色保留以便恢复.
 func (s *UserService) Delete(ctx context.Context, username string) error {
-	// check if User exists
-	_, err := s.db.GetUser(ctx, username)
+	// check if User exists，副本的复制延迟可能导致刚创建的用户被当作不存在，因此读取主库
+	_, err := s.db(ctx).GetUser(dao.WithPrimary(ctx), username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil
@@ -211,25 +409,80 @@ func (s *UserService) Delete(ctx context.Context, username string) error {
 		return fmt.Errorf("- UserService - Delete - get failed: %w", err)
 	}
 
-	err = s.db.DeleteUser(ctx, username)
+	err = s.db(ctx).DeleteUser(ctx, username)
 	if err != nil {
 		return fmt.Errorf("- UserService - Delete - delete failed: %w", err)
 	}
 
 	// 删除缓存
-	key := UserCacheKeyPrefix + username
+	s.invalidate(ctx, UserCacheKeyPrefix+username, PermissionCacheKeyPrefix+username)
+
+	return nil
+}
+
+// Restore - 恢复已软删除的 User.
+func (s *UserService) Restore(ctx context.Context, username string) (entity.User, error) {
+	var u dao.User
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+		n, err := q.RestoreUser(ctx, username)
+		if err != nil {
+			return fmt.Errorf("- UserService - Restore - restore failed: %w", err)
+		}
 
-	err = s.cache.Del(ctx, key)
+		if n == 0 {
+			return exception.NotFound(fmt.Errorf("deleted user %s not found", username))
+		}
+
+		u, err = q.GetUser(ctx, username)
+		if err != nil {
+			return fmt.Errorf("- UserService - Restore - get failed: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.l.Ctx(ctx).Warnf("UserService - Update - del cache %s failed: %v", key, err)
+		return entity.User{}, err
 	}
 
-	return nil
+	// 删除负缓存
+	s.invalidate(ctx, UserCacheKeyPrefix+username, PermissionCacheKeyPrefix+username)
+
+	return entity.ToUser(u), nil
+}
+
+// Purge - 永久删除 before 之前软删除的 User 及其角色，返回删除的用户数.
+func (s *UserService) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+
+	// 角色和用户在同一个事务中删除，避免只删除了角色的用户被恢复
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, q dao.Querier) error {
+		err := q.PurgeDeletedUserRoles(ctx, before)
+		if err != nil {
+			return fmt.Errorf("- UserService - Purge - purge roles failed: %w", err)
+		}
+
+		n, err = q.PurgeDeletedUsers(ctx, before)
+		if err != nil {
+			return fmt.Errorf("- UserService - Purge - purge users failed: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if n > 0 {
+		s.invalidate(ctx)
+	}
+
+	return n, nil
 }
 
 // AuthenticationPassword - 验证用户密码.
 func (s *UserService) AuthenticationPassword(ctx context.Context, username, pass string) (bool, string, error) {
-	u, err := s.db.GetUser(ctx, username)
+	u, err := s.db(ctx).GetUser(ctx, username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, fmt.Sprintf("User %s not found", username), nil
@@ -240,8 +493,40 @@ func (s *UserService) AuthenticationPassword(ctx context.Context, username, pass
 
 	err = password.CompareHashAndPassword(u.Password, pass)
 	if err != nil {
-		return false, err.Error(), err
+		return false, "Password mismatch", nil
 	}
 
 	return true, "", nil
 }
+
+// 删除用户相关的缓存并使列表缓存失效，失败时只记录日志.
+func (s *UserService) invalidate(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.cache.Del(ctx, key); err != nil {
+			s.l.Ctx(ctx).Warnf("UserService - invalidate - del cache %s failed: %v", key, err)
+		}
+	}
+
+	if err := s.tags.Invalidate(ctx, UsersCacheTag); err != nil {
+		s.l.Ctx(ctx).Warnf("UserService - invalidate - invalidate tag %s failed: %v", UsersCacheTag, err)
+	}
+}
+
+// nil 代表不修改，对应 UpdateUser 中的 coalesce(NULL, column).
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// 用于负缓存判定，只缓存用户不存在的结果.
+func isNotFound(err error) bool {
+	return exception.Is(err, exception.NotFound(nil))
+}
+
+// 返回 ctx 中事务的 Querier，不在事务中时返回非事务的 Querier.
+func (s *UserService) db(ctx context.Context) dao.Querier {
+	return s.tx.Querier(ctx)
+}
@@ -5,10 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
+	"sync"
 	"testing"
+	"time"
 
+	gomysql "github.com/go-sql-driver/mysql"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ninehills/go-webapp-template/internal/dao"
@@ -31,16 +36,37 @@ type test struct {
 	err  error
 }
 
-func bootstrap(t *testing.T) (*service.UserService, *mocks.MockQuerier, *mocks.MockCacher) {
+// bootstrap 的可选配置.
+type bootstrapOption func(deps *dependency.Dependency)
+
+// 使用真实的缓存（如 cache.NewMemory）代替 mock 的 Cacher，此时返回的 MockCacher 不会被调用.
+func withCache(c cache.Cacher) bootstrapOption {
+	return func(deps *dependency.Dependency) {
+		deps.Cache = c
+	}
+}
+
+func bootstrap(t *testing.T, opts ...bootstrapOption) (*service.UserService, *mocks.MockQuerier, *mocks.MockCacher) {
 	t.Helper()
 
+	userService, querier, cacher, _ := bootstrapWithAuthz(t, opts...)
+
+	return userService, querier, cacher
+}
+
+func bootstrapWithAuthz(t *testing.T, opts ...bootstrapOption) (
+	*service.UserService, *mocks.MockQuerier, *mocks.MockCacher, *mocks.MockAuthz,
+) {
+	t.Helper()
+
+	// gomock.NewController(t) 在测试结束时检查是否缺少调用，不能在此 defer Finish
 	mockCtl := gomock.NewController(t)
-	defer mockCtl.Finish()
 
 	querier := mocks.NewMockQuerier(mockCtl)
 	cacher := mocks.NewMockCacher(mockCtl)
+	authz := mocks.NewMockAuthz(mockCtl)
 
-	userService := service.NewUserService(&dependency.Dependency{
+	deps := &dependency.Dependency{
 		DAO: querier,
 		Logger: logger.New(logger.Config{
 			Format:  "text",
@@ -48,9 +74,43 @@ func bootstrap(t *testing.T) (*service.UserService, *mocks.MockQuerier, *mocks.M
 			NoColor: false,
 		}),
 		Cache: cacher,
-	}, &service.Services{})
+	}
+	for _, opt := range opts {
+		opt(deps)
+	}
 
-	return userService, querier, cacher
+	userService := service.NewUserService(deps, &service.Services{Authz: authz})
+
+	return userService, querier, cacher, authz
+}
+
+// 按 ReadThrough 的缓存条目格式编码 user，条目在一分钟后过期.
+func userCacheEntry(t *testing.T, user entity.User) []byte {
+	t.Helper()
+
+	value, err := cache.Gob.Marshal(user)
+	require.NoError(t, err)
+
+	data, err := cache.Gob.Marshal(struct {
+		Value    []byte
+		ExpireAt int64
+	}{value, time.Now().Add(time.Minute).UnixMilli()})
+	require.NoError(t, err)
+
+	return data
+}
+
+// 匹配要求读取主库的 ctx.
+type primaryCtx struct{}
+
+func (primaryCtx) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+
+	return ok && dao.UsePrimary(ctx)
+}
+
+func (primaryCtx) String() string {
+	return "is a context reading the primary"
 }
 
 // 自定义 UserMatcher，只比较 Username.
@@ -73,7 +133,14 @@ func (e userMatcher) String() string {
 
 func TestUserCreate(t *testing.T) {
 	t.Parallel()
-	userService, querier, _ := bootstrap(t)
+	userService, querier, cacher, authz := bootstrapWithAuthz(t)
+
+	// 创建成功后删除负缓存和权限缓存，并使列表缓存失效
+	expectInvalidate := func(id string) {
+		cacher.EXPECT().Del(context.Background(), service.UserCacheKeyPrefix+id).Return(nil)
+		cacher.EXPECT().Del(context.Background(), service.PermissionCacheKeyPrefix+id).Return(nil)
+		cacher.EXPECT().SetBytes(context.Background(), gomock.Any(), gomock.Any(), cache.DefaultTagTTL).Return(nil)
+	}
 
 	tests := []test{
 		{
@@ -82,7 +149,7 @@ func TestUserCreate(t *testing.T) {
 			mock: func(id string) {
 				querier.EXPECT().CreateUser(
 					context.Background(), userMatcher{id},
-				).Return(errors.New(":Duplicate entry"))
+				).Return(&gomysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'user.username'"})
 			},
 			res: entity.User{},
 			err: exception.Conflict(nil),
@@ -105,6 +172,7 @@ func TestUserCreate(t *testing.T) {
 				querier.EXPECT().CreateUser(
 					context.Background(), userMatcher{id},
 				).Return(nil)
+				authz.EXPECT().AssignRole(context.Background(), id, entity.RoleUser).Return(nil)
 				querier.EXPECT().GetUser(context.Background(), id).Return(dao.User{}, errInternal)
 			},
 			res: entity.User{},
@@ -117,6 +185,8 @@ func TestUserCreate(t *testing.T) {
 				querier.EXPECT().CreateUser(
 					context.Background(), userMatcher{id},
 				).Return(nil)
+				expectInvalidate(id)
+				authz.EXPECT().AssignRole(context.Background(), id, entity.RoleUser).Return(nil)
 				querier.EXPECT().GetUser(context.Background(), id).Return(dao.User{}, nil)
 			},
 			res: entity.User{},
@@ -138,6 +208,32 @@ func TestUserCreate(t *testing.T) {
 	}
 }
 
+// 冲突时返回具体的字段.
+func TestUserCreateDuplicate(t *testing.T) {
+	t.Parallel()
+
+	userService, querier, _ := bootstrap(t)
+	ctx := context.Background()
+
+	for _, field := range []string{"username", "email"} {
+		// MySQL 和 PostgreSQL 的唯一索引冲突返回相同的错误
+		for _, dbErr := range []error{
+			&gomysql.MySQLError{Number: 1062, Message: fmt.Sprintf("Duplicate entry 'x' for key 'user.%s'", field)},
+			&pgconn.PgError{Code: "23505", TableName: "user", ConstraintName: "user_" + field + "_key"},
+		} {
+			querier.EXPECT().CreateUser(ctx, gomock.Any()).Return(fmt.Errorf("exec: %w", dbErr))
+
+			_, err := userService.Create(ctx, entity.User{Username: "alice", Email: "a@example.com"})
+
+			var e *exception.Error
+			require.ErrorAs(t, err, &e)
+			require.Equal(t, http.StatusConflict, e.Code())
+			require.Equal(t, field, e.Field())
+			require.Equal(t, field+" already in use", e.Error())
+		}
+	}
+}
+
 func TestUserGet(t *testing.T) {
 	t.Parallel()
 
@@ -196,23 +292,26 @@ func TestUserCacheGet(t *testing.T) {
 		return fmt.Sprintf("%s%s", service.UserCacheKeyPrefix, username)
 	}
 
+	hitID := uuid.NewString()
+
 	tests := []test{
 		{
 			name: "CacheGet() - cache hit",
-			id:   uuid.NewString(),
+			id:   hitID,
 			mock: func(id string) {
-				cacher.EXPECT().Get(context.Background(), userCacheKey(id), gomock.Any()).Return(nil)
+				// 命中时不查询数据库
+				cacher.EXPECT().GetBytes(context.Background(), userCacheKey(id)).Return(userCacheEntry(t, entity.User{Username: id}), nil)
 			},
-			res: entity.User{},
+			res: entity.User{Username: hitID},
 			err: nil,
 		},
 		{
 			name: "CacheGet() - cache failed but db success",
 			id:   uuid.NewString(),
 			mock: func(id string) {
-				cacher.EXPECT().Get(context.Background(), userCacheKey(id), gomock.Any()).Return(errInternal)
-				querier.EXPECT().GetUser(context.Background(), id).Return(dao.User{}, nil)
-				cacher.EXPECT().Set(context.Background(), userCacheKey(id), gomock.Any()).Return(nil)
+				cacher.EXPECT().GetBytes(context.Background(), userCacheKey(id)).Return(nil, errInternal)
+				querier.EXPECT().GetUser(primaryCtx{}, id).Return(dao.User{}, nil)
+				cacher.EXPECT().SetBytes(gomock.Any(), userCacheKey(id), gomock.Any(), gomock.Any()).Return(nil)
 			},
 			res: entity.User{},
 			err: nil,
@@ -221,8 +320,8 @@ func TestUserCacheGet(t *testing.T) {
 			name: "CacheGet() - cache miss and db fail",
 			id:   uuid.NewString(),
 			mock: func(id string) {
-				cacher.EXPECT().Get(context.Background(), userCacheKey(id), gomock.Any()).Return(cache.ErrMiss)
-				querier.EXPECT().GetUser(context.Background(), id).Return(dao.User{}, errInternal)
+				cacher.EXPECT().GetBytes(context.Background(), userCacheKey(id)).Return(nil, cache.ErrMiss)
+				querier.EXPECT().GetUser(primaryCtx{}, id).Return(dao.User{}, errInternal)
 			},
 			res: entity.User{},
 			err: errInternal,
@@ -231,9 +330,9 @@ func TestUserCacheGet(t *testing.T) {
 			name: "CacheGet() - 缓存设定失败，但是整个 CacheGet 会成功",
 			id:   uuid.NewString(),
 			mock: func(id string) {
-				cacher.EXPECT().Get(context.Background(), userCacheKey(id), gomock.Any()).Return(cache.ErrMiss)
-				querier.EXPECT().GetUser(context.Background(), id).Return(dao.User{}, nil)
-				cacher.EXPECT().Set(context.Background(), userCacheKey(id), gomock.Any()).Return(errInternal)
+				cacher.EXPECT().GetBytes(context.Background(), userCacheKey(id)).Return(nil, cache.ErrMiss)
+				querier.EXPECT().GetUser(primaryCtx{}, id).Return(dao.User{}, nil)
+				cacher.EXPECT().SetBytes(gomock.Any(), userCacheKey(id), gomock.Any(), gomock.Any()).Return(errInternal)
 			},
 			res: entity.User{},
 			err: nil,
@@ -253,3 +352,202 @@ func TestUserCacheGet(t *testing.T) {
 		})
 	}
 }
+
+func TestUserCacheGetReadThrough(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewMemory(100, time.Minute)
+	userService, querier, _ := bootstrap(t, withCache(c))
+
+	ctx := context.Background()
+
+	// 并发未命中只查询一次数据库，之后命中缓存；写入之后缓存被删除，重新加载时读取主库
+	querier.EXPECT().GetUser(primaryCtx{}, "alice").DoAndReturn(func(context.Context, string) (dao.User, error) {
+		time.Sleep(20 * time.Millisecond)
+
+		return dao.User{ID: 1, Username: "alice"}, nil
+	}).Times(1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			u, err := userService.CacheGet(ctx, "alice")
+			require.NoError(t, err)
+			require.Equal(t, "alice", u.Username)
+		}()
+	}
+
+	wg.Wait()
+
+	// gob 编码保留了 json:"-" 的 ID
+	u, err := userService.CacheGet(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), u.ID)
+
+	// 缓存中不包含密码字段
+	data, err := c.GetBytes(ctx, service.UserCacheKeyPrefix+"alice")
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "Password")
+
+	// 不存在的用户被负缓存，第二次不查询数据库
+	querier.EXPECT().GetUser(primaryCtx{}, "nobody").Return(dao.User{}, sql.ErrNoRows).Times(1)
+
+	for i := 0; i < 2; i++ {
+		_, err = userService.CacheGet(ctx, "nobody")
+		require.True(t, exception.Is(err, exception.NotFound(nil))) //nolint:testifylint
+	}
+}
+
+func TestUserQueryCache(t *testing.T) {
+	t.Parallel()
+
+	userService, querier, _ := bootstrap(t, withCache(cache.NewMemory(100, time.Minute)))
+
+	ctx := context.Background()
+	page := entity.PageQuery{PageNo: 1, PageSize: 10}
+
+	querier.EXPECT().QueryUser(primaryCtx{}, gomock.Any()).Return([]dao.User{{ID: 1, Username: "alice"}}, int64(1), nil).Times(1)
+
+	// 等价的查询条件（排序方向大小写、默认排序列）命中同一个缓存
+	for _, o := range []entity.OrderQuery{{}, {Order: "ASC", OrderBy: "id"}} {
+		res, users, err := userService.Query(ctx, page, o, entity.UserQuery{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.TotalCount)
+		require.Len(t, users, 1)
+		require.Equal(t, int64(1), users[0].ID)
+	}
+
+	// 删除用户后列表缓存失效，删除前读取主库判断用户是否存在
+	querier.EXPECT().GetUser(dao.WithPrimary(ctx), "alice").Return(dao.User{ID: 1, Username: "alice"}, nil)
+	querier.EXPECT().DeleteUser(ctx, "alice").Return(nil)
+	require.NoError(t, userService.Delete(ctx, "alice"))
+
+	querier.EXPECT().QueryUser(primaryCtx{}, gomock.Any()).Return([]dao.User{}, int64(0), nil).Times(1)
+
+	res, users, err := userService.Query(ctx, page, entity.OrderQuery{}, entity.UserQuery{})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), res.TotalCount)
+	require.NotNil(t, users)
+	require.Empty(t, users)
+}
+
+func TestUserRestorePurge(t *testing.T) {
+	t.Parallel()
+
+	userService, querier, _ := bootstrap(t, withCache(cache.NewMemory(100, time.Minute)))
+
+	ctx := context.Background()
+
+	// 软删除后 GetUser 查不到，负缓存在恢复时被删除
+	querier.EXPECT().GetUser(gomock.Any(), "alice").Return(dao.User{}, sql.ErrNoRows)
+
+	_, err := userService.CacheGet(ctx, "alice")
+	require.True(t, exception.Is(err, exception.NotFound(nil))) //nolint:testifylint
+
+	querier.EXPECT().RestoreUser(ctx, "alice").Return(int64(1), nil)
+	querier.EXPECT().GetUser(gomock.Any(), "alice").Return(dao.User{ID: 1, Username: "alice", Version: 3}, nil).Times(2)
+
+	u, err := userService.Restore(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), u.Version)
+
+	u, err = userService.CacheGet(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "alice", u.Username)
+
+	// 用户不存在或未被删除
+	querier.EXPECT().RestoreUser(ctx, "bob").Return(int64(0), nil)
+
+	_, err = userService.Restore(ctx, "bob")
+	require.True(t, exception.Is(err, exception.NotFound(nil))) //nolint:testifylint
+
+	// 先删除角色，再删除用户
+	before := time.Now().UTC().Add(-time.Hour)
+	gomock.InOrder(
+		querier.EXPECT().PurgeDeletedUserRoles(ctx, before).Return(nil),
+		querier.EXPECT().PurgeDeletedUsers(ctx, before).Return(int64(2), nil),
+	)
+
+	n, err := userService.Purge(ctx, before)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+}
+
+func TestUserUpdateVersion(t *testing.T) {
+	t.Parallel()
+
+	userService, querier, _ := bootstrap(t, withCache(cache.NewMemory(100, time.Minute)))
+
+	ctx := context.Background()
+	// 更新前后读取用户都使用主库
+	primary := dao.WithPrimary(ctx)
+	current := dao.User{ID: 1, Username: "alice", Version: 3}
+
+	// 期望的版本与当前版本不一致，不执行更新
+	querier.EXPECT().GetUser(primary, "alice").Return(current, nil)
+
+	_, err := userService.Update(ctx, entity.User{Username: "alice", Description: "x", Version: 2})
+	require.True(t, exception.Is(err, exception.PreconditionFailed(nil))) //nolint:testifylint
+
+	// 读取之后被并发修改，条件更新影响 0 行
+	querier.EXPECT().GetUser(primary, "alice").Return(current, nil)
+	querier.EXPECT().UpdateUser(primary, gomock.Any()).DoAndReturn(
+		func(_ context.Context, arg dao.UpdateUserParams) (int64, error) {
+			require.Equal(t, int64(3), arg.Version)
+
+			return 0, nil
+		})
+
+	_, err = userService.Update(ctx, entity.User{Username: "alice", Description: "x"})
+	require.True(t, exception.Is(err, exception.PreconditionFailed(nil))) //nolint:testifylint
+
+	// 版本一致时更新成功，返回新版本
+	querier.EXPECT().GetUser(primary, "alice").Return(current, nil)
+	querier.EXPECT().UpdateUser(primary, gomock.Any()).Return(int64(1), nil)
+	querier.EXPECT().GetUser(primary, "alice").Return(dao.User{ID: 1, Username: "alice", Description: "x", Version: 4}, nil)
+
+	u, err := userService.Update(ctx, entity.User{Username: "alice", Description: "x", Version: 3})
+	require.NoError(t, err)
+	require.Equal(t, int64(4), u.Version)
+}
+
+func TestUserPatch(t *testing.T) {
+	t.Parallel()
+
+	userService, querier, _ := bootstrap(t, withCache(cache.NewMemory(100, time.Minute)))
+
+	ctx := context.Background()
+	// 更新前后读取用户都使用主库
+	primary := dao.WithPrimary(ctx)
+	empty := ""
+
+	// 非 nil 的空字符串会清空字段，nil 的字段不修改
+	querier.EXPECT().GetUser(primary, "alice").Return(dao.User{ID: 1, Username: "alice", Description: "x", Version: 1}, nil)
+	querier.EXPECT().UpdateUser(primary, dao.UpdateUserParams{
+		Username:    "alice",
+		Version:     1,
+		Description: sql.NullString{String: "", Valid: true},
+	}).Return(int64(1), nil)
+	querier.EXPECT().GetUser(primary, "alice").Return(dao.User{ID: 1, Username: "alice", Version: 2}, nil)
+
+	u, err := userService.Patch(ctx, entity.UserPatch{Username: "alice", Description: &empty})
+	require.NoError(t, err)
+	require.Empty(t, u.Description)
+
+	// Update 中的空字符串代表不修改
+	querier.EXPECT().GetUser(primary, "alice").Return(dao.User{ID: 1, Username: "alice", Version: 2}, nil)
+	querier.EXPECT().UpdateUser(primary, dao.UpdateUserParams{
+		Username: "alice",
+		Version:  2,
+		Status:   sql.NullInt32{Int32: entity.UserStatusInactive, Valid: true},
+	}).Return(int64(1), nil)
+	querier.EXPECT().GetUser(primary, "alice").Return(dao.User{ID: 1, Username: "alice", Version: 3}, nil)
+
+	_, err = userService.Update(ctx, entity.User{Username: "alice", Status: entity.UserStatusInactive})
+	require.NoError(t, err)
+}
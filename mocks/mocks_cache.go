@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -62,6 +63,36 @@ func (mr *MockCacherMockRecorder) Get(ctx, key, target interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacher)(nil).Get), ctx, key, target)
 }
 
+// GetBytes mocks base method.
+func (m *MockCacher) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBytes", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBytes indicates an expected call of GetBytes.
+func (mr *MockCacherMockRecorder) GetBytes(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBytes", reflect.TypeOf((*MockCacher)(nil).GetBytes), ctx, key)
+}
+
+// MGetBytes mocks base method.
+func (m *MockCacher) MGetBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MGetBytes", ctx, keys)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MGetBytes indicates an expected call of MGetBytes.
+func (mr *MockCacherMockRecorder) MGetBytes(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MGetBytes", reflect.TypeOf((*MockCacher)(nil).MGetBytes), ctx, keys)
+}
+
 // Set mocks base method.
 func (m *MockCacher) Set(ctx context.Context, key string, value interface{}) error {
 	m.ctrl.T.Helper()
@@ -75,3 +106,54 @@ func (mr *MockCacherMockRecorder) Set(ctx, key, value interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacher)(nil).Set), ctx, key, value)
 }
+
+// SetBytes mocks base method.
+func (m *MockCacher) SetBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBytes", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBytes indicates an expected call of SetBytes.
+func (mr *MockCacherMockRecorder) SetBytes(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBytes", reflect.TypeOf((*MockCacher)(nil).SetBytes), ctx, key, value, ttl)
+}
+
+// MockFiller is a mock of Filler interface.
+type MockFiller struct {
+	ctrl     *gomock.Controller
+	recorder *MockFillerMockRecorder
+}
+
+// MockFillerMockRecorder is the mock recorder for MockFiller.
+type MockFillerMockRecorder struct {
+	mock *MockFiller
+}
+
+// NewMockFiller creates a new mock instance.
+func NewMockFiller(ctrl *gomock.Controller) *MockFiller {
+	mock := &MockFiller{ctrl: ctrl}
+	mock.recorder = &MockFillerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFiller) EXPECT() *MockFillerMockRecorder {
+	return m.recorder
+}
+
+// FillBytes mocks base method.
+func (m *MockFiller) FillBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillBytes", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FillBytes indicates an expected call of FillBytes.
+func (mr *MockFillerMockRecorder) FillBytes(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillBytes", reflect.TypeOf((*MockFiller)(nil).FillBytes), ctx, key, value, ttl)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	dao "github.com/ninehills/go-webapp-template/internal/dao"
@@ -35,6 +36,48 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 	return m.recorder
 }
 
+// AddRolePermission mocks base method.
+func (m *MockQuerier) AddRolePermission(ctx context.Context, arg dao.AddRolePermissionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRolePermission", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRolePermission indicates an expected call of AddRolePermission.
+func (mr *MockQuerierMockRecorder) AddRolePermission(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRolePermission", reflect.TypeOf((*MockQuerier)(nil).AddRolePermission), ctx, arg)
+}
+
+// AssignUserRole mocks base method.
+func (m *MockQuerier) AssignUserRole(ctx context.Context, arg dao.AssignUserRoleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUserRole", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignUserRole indicates an expected call of AssignUserRole.
+func (mr *MockQuerierMockRecorder) AssignUserRole(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUserRole", reflect.TypeOf((*MockQuerier)(nil).AssignUserRole), ctx, arg)
+}
+
+// CreateRole mocks base method.
+func (m *MockQuerier) CreateRole(ctx context.Context, arg dao.CreateRoleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockQuerierMockRecorder) CreateRole(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockQuerier)(nil).CreateRole), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockQuerier) CreateUser(ctx context.Context, arg dao.CreateUserParams) error {
 	m.ctrl.T.Helper()
@@ -63,6 +106,35 @@ func (mr *MockQuerierMockRecorder) DeleteUser(ctx, username interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockQuerier)(nil).DeleteUser), ctx, username)
 }
 
+// DeleteUserRoles mocks base method.
+func (m *MockQuerier) DeleteUserRoles(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRoles", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRoles indicates an expected call of DeleteUserRoles.
+func (mr *MockQuerierMockRecorder) DeleteUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRoles", reflect.TypeOf((*MockQuerier)(nil).DeleteUserRoles), ctx, userID)
+}
+
+// GetRole mocks base method.
+func (m *MockQuerier) GetRole(ctx context.Context, name string) (dao.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", ctx, name)
+	ret0, _ := ret[0].(dao.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockQuerierMockRecorder) GetRole(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockQuerier)(nil).GetRole), ctx, name)
+}
+
 // GetUser mocks base method.
 func (m *MockQuerier) GetUser(ctx context.Context, username string) (dao.User, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +150,21 @@ func (mr *MockQuerierMockRecorder) GetUser(ctx, username interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockQuerier)(nil).GetUser), ctx, username)
 }
 
+// ListRolePermissions mocks base method.
+func (m *MockQuerier) ListRolePermissions(ctx context.Context, roleID int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRolePermissions", ctx, roleID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRolePermissions indicates an expected call of ListRolePermissions.
+func (mr *MockQuerierMockRecorder) ListRolePermissions(ctx, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRolePermissions", reflect.TypeOf((*MockQuerier)(nil).ListRolePermissions), ctx, roleID)
+}
+
 // ListUser mocks base method.
 func (m *MockQuerier) ListUser(ctx context.Context, arg dao.ListUserParams) ([]dao.User, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +180,65 @@ func (mr *MockQuerierMockRecorder) ListUser(ctx, arg interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUser", reflect.TypeOf((*MockQuerier)(nil).ListUser), ctx, arg)
 }
 
+// ListUserPermissions mocks base method.
+func (m *MockQuerier) ListUserPermissions(ctx context.Context, username string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserPermissions", ctx, username)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPermissions indicates an expected call of ListUserPermissions.
+func (mr *MockQuerierMockRecorder) ListUserPermissions(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPermissions", reflect.TypeOf((*MockQuerier)(nil).ListUserPermissions), ctx, username)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockQuerier) ListUserRoles(ctx context.Context, userID int64) ([]dao.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]dao.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockQuerierMockRecorder) ListUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockQuerier)(nil).ListUserRoles), ctx, userID)
+}
+
+// PurgeDeletedUserRoles mocks base method.
+func (m *MockQuerier) PurgeDeletedUserRoles(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUserRoles", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletedUserRoles indicates an expected call of PurgeDeletedUserRoles.
+func (mr *MockQuerierMockRecorder) PurgeDeletedUserRoles(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUserRoles", reflect.TypeOf((*MockQuerier)(nil).PurgeDeletedUserRoles), ctx, before)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockQuerier) PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockQuerierMockRecorder) PurgeDeletedUsers(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockQuerier)(nil).PurgeDeletedUsers), ctx, before)
+}
+
 // QueryUser mocks base method.
 func (m *MockQuerier) QueryUser(ctx context.Context, arg dao.QueryUserParams) ([]dao.User, int64, error) {
 	m.ctrl.T.Helper()
@@ -109,12 +255,28 @@ func (mr *MockQuerierMockRecorder) QueryUser(ctx, arg interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUser", reflect.TypeOf((*MockQuerier)(nil).QueryUser), ctx, arg)
 }
 
+// RestoreUser mocks base method.
+func (m *MockQuerier) RestoreUser(ctx context.Context, username string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, username)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockQuerierMockRecorder) RestoreUser(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockQuerier)(nil).RestoreUser), ctx, username)
+}
+
 // UpdateUser mocks base method.
-func (m *MockQuerier) UpdateUser(ctx context.Context, arg dao.UpdateUserParams) error {
+func (m *MockQuerier) UpdateUser(ctx context.Context, arg dao.UpdateUserParams) (int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateUser", ctx, arg)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
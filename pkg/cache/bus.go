@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+const busRetryDelay = time.Second
+
+type busMessage struct {
+	Node string   `json:"node"`
+	Keys []string `json:"keys"`
+}
+
+// Bus 通过 Redis pub/sub 在多个实例之间广播失效的 key，收到消息的实例删除本地 L1 中的 key.
+// 订阅断开期间可能丢失消息，因此断线时会清空本地缓存，go-redis 会在下次读取时自动重连并重新订阅.
+type Bus struct {
+	rdb     *redis.Client
+	channel string
+	node    string
+	local   *Memory
+	l       logger.Logger
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewBus 订阅 channel 并在后台处理失效消息，退出时需要调用 Close.
+func NewBus(rdb *redis.Client, channel string, local *Memory, l logger.Logger) *Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &Bus{
+		rdb:     rdb,
+		channel: channel,
+		node:    uuid.NewString(),
+		local:   local,
+		l:       l,
+		pubsub:  rdb.Subscribe(ctx, channel),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go b.run(ctx)
+
+	return b
+}
+
+// Publish 广播失效的 key，本实例的 L1 由调用方自己删除.
+func (b *Bus) Publish(ctx context.Context, keys ...string) error {
+	payload, err := json.Marshal(busMessage{Node: b.node, Keys: keys})
+	if err != nil {
+		return ErrMarshal
+	}
+
+	err = b.rdb.Publish(ctx, b.channel, payload).Err()
+	if err != nil {
+		busErrors.WithLabelValues("publish").Inc()
+
+		return ErrStorage
+	}
+
+	busPublished.Add(float64(len(keys)))
+
+	return nil
+}
+
+// Close 取消订阅并等待后台 goroutine 退出.
+func (b *Bus) Close() {
+	b.once.Do(func() {
+		b.cancel()
+		b.pubsub.Close()
+		<-b.done
+	})
+}
+
+func (b *Bus) run(ctx context.Context) {
+	defer close(b.done)
+
+	disconnected := false
+
+	for {
+		msg, err := b.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			busErrors.WithLabelValues("receive").Inc()
+			b.l.Warnf("cache - Bus - receive from %s failed, purge local cache and retry: %v", b.channel, err)
+
+			// 断线期间的失效消息会丢失，只能清空本地缓存
+			b.local.Purge()
+
+			disconnected = true
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(busRetryDelay):
+			}
+
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if disconnected && m.Kind == "subscribe" {
+				busReconnects.Inc()
+				b.l.Infof("cache - Bus - resubscribed to %s", b.channel)
+				// 重连到订阅成功之间也可能丢失消息
+				b.local.Purge()
+
+				disconnected = false
+			}
+		case *redis.Message:
+			b.handle(ctx, m.Payload)
+		}
+	}
+}
+
+func (b *Bus) handle(ctx context.Context, payload string) {
+	var m busMessage
+
+	if err := json.Unmarshal([]byte(payload), &m); err != nil {
+		busErrors.WithLabelValues("decode").Inc()
+		b.l.Warnf("cache - Bus - decode message %q failed: %v", payload, err)
+
+		return
+	}
+
+	if m.Node == b.node {
+		return
+	}
+
+	for _, key := range m.Keys {
+		_ = b.local.Del(ctx, key)
+	}
+
+	busReceived.Add(float64(len(m.Keys)))
+}
@@ -0,0 +1,131 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+type replica struct {
+	local *cache.Memory
+	bus   *cache.Bus
+	cache *cache.Tiered
+}
+
+func newReplica(t *testing.T, addr string) replica {
+	t.Helper()
+
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { rdb.Close() })
+
+	local := cache.NewMemory(10, time.Minute)
+	bus := cache.NewBus(rdb, "cache:invalidate", local, logger.New(logger.Config{Level: "error"}))
+	t.Cleanup(bus.Close)
+
+	return replica{
+		local: local,
+		bus:   bus,
+		cache: cache.NewTiered(local, cache.NewCache(rdb, time.Minute), bus, time.Minute),
+	}
+}
+
+func waitSubscribed(t *testing.T, m *miniredis.Miniredis, n int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return m.PubSubNumSub("cache:invalidate")["cache:invalidate"] == n
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBusInvalidatesOtherReplicas(t *testing.T) {
+	t.Parallel()
+
+	m := miniredis.RunT(t)
+	ctx := context.Background()
+
+	a, b := newReplica(t, m.Addr()), newReplica(t, m.Addr())
+	waitSubscribed(t, m, 2)
+
+	var v int
+	require.NoError(t, a.cache.Set(ctx, "cache:user:alice", 1))
+	require.NoError(t, b.cache.Get(ctx, "cache:user:alice", &v))
+	require.NoError(t, b.local.Get(ctx, "cache:user:alice", &v))
+
+	// a 更新后 b 的本地缓存被删除，再次读取得到新值
+	require.NoError(t, a.cache.Set(ctx, "cache:user:alice", 2))
+	require.Eventually(t, func() bool {
+		return b.local.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, b.cache.Get(ctx, "cache:user:alice", &v))
+	require.Equal(t, 2, v)
+
+	// a 删除后 b 的本地缓存也被删除
+	require.NoError(t, a.cache.Del(ctx, "cache:user:alice"))
+	require.Eventually(t, func() bool {
+		return b.local.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+	require.ErrorIs(t, b.cache.Get(ctx, "cache:user:alice", &v), cache.ErrMiss)
+
+	// 自己发出的消息不会删除自己的本地缓存
+	require.NoError(t, a.cache.Set(ctx, "cache:user:bob", 1))
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, a.local.Get(ctx, "cache:user:bob", &v))
+}
+
+func TestBusPurgesLocalOnReconnect(t *testing.T) {
+	t.Parallel()
+
+	m := miniredis.RunT(t)
+	ctx := context.Background()
+
+	r := newReplica(t, m.Addr())
+	waitSubscribed(t, m, 1)
+
+	require.NoError(t, r.cache.Set(ctx, "k", 1))
+	require.Equal(t, 1, r.local.Len())
+
+	m.Close()
+	require.Eventually(t, func() bool {
+		return r.local.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, m.Restart())
+	waitSubscribed(t, m, 1)
+}
+
+func TestBusSkipsFills(t *testing.T) {
+	t.Parallel()
+
+	m := miniredis.RunT(t)
+	ctx := context.Background()
+
+	a, b := newReplica(t, m.Addr()), newReplica(t, m.Addr())
+	waitSubscribed(t, m, 2)
+
+	require.NoError(t, b.local.SetBytes(ctx, "cache:user:alice", []byte("1"), 0))
+
+	// 读穿透未命中时的回填不广播，b 的本地缓存不受影响
+	rt := cache.NewReadThrough(a.cache)
+
+	var v int
+	require.NoError(t, rt.Get(ctx, "cache:user:alice", &v, func(context.Context) (interface{}, error) { return 2, nil }))
+	require.Equal(t, 2, v)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := b.local.GetBytes(ctx, "cache:user:alice")
+	require.NoError(t, err)
+
+	// 覆盖写入时广播
+	require.NoError(t, a.cache.SetBytes(ctx, "cache:user:alice", []byte("3"), 0))
+	require.Eventually(t, func() bool {
+		return b.local.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+}
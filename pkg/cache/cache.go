@@ -2,11 +2,12 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const DefaultCacheExpires = 5 * time.Minute
@@ -32,30 +33,85 @@ func NewCache(r *redis.Client, expires time.Duration) *Cache {
 
 // 从 Cache 中获取对象.
 func (c *Cache) Get(ctx context.Context, key string, target interface{}) error {
-	result, err := c.redis.Get(ctx, key).Bytes()
+	return getJSON(ctx, c, backendRedis, key, target)
+}
+
+// 写入 Cache.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}) error {
+	return setJSON(ctx, c, backendRedis, key, value)
+}
+
+// GetBytes -.
+func (c *Cache) GetBytes(ctx context.Context, key string) (result []byte, err error) {
+	ctx, span := startSpan(ctx, "get", key)
+	defer func() { endSpan(span, err) }()
+
+	result, err = c.redis.Get(ctx, key).Bytes()
 	if errors.Is(err, redis.Nil) {
-		return ErrMiss
+		cacheMisses.WithLabelValues(backendRedis, keyPrefix(key)).Inc()
+
+		return nil, ErrMiss
 	} else if err != nil {
-		return ErrStorage
+		cacheErrors.WithLabelValues(backendRedis, "get", keyPrefix(key)).Inc()
+
+		return nil, ErrStorage
+	}
+
+	cacheHits.WithLabelValues(backendRedis, keyPrefix(key)).Inc()
+	span.SetAttributes(attribute.Bool("cache.hit", true))
+
+	return result, nil
+}
+
+// MGetBytes -.
+func (c *Cache) MGetBytes(ctx context.Context, keys []string) (results [][]byte, err error) {
+	ctx, span := startSpan(ctx, "mget", strings.Join(keys, " "))
+	defer func() { endSpan(span, err) }()
+
+	if len(keys) == 0 {
+		return [][]byte{}, nil
 	}
 
-	err = json.Unmarshal(result, target)
+	values, err := c.redis.MGet(ctx, keys...).Result()
 	if err != nil {
-		return ErrUnmarshal
+		for _, key := range keys {
+			cacheErrors.WithLabelValues(backendRedis, "mget", keyPrefix(key)).Inc()
+		}
+
+		return nil, ErrStorage
 	}
 
-	return nil
+	results = make([][]byte, len(keys))
+
+	for i, v := range values {
+		// 不存在的 key 返回 nil，其他值都是 string
+		s, ok := v.(string)
+		if !ok {
+			cacheMisses.WithLabelValues(backendRedis, keyPrefix(keys[i])).Inc()
+
+			continue
+		}
+
+		cacheHits.WithLabelValues(backendRedis, keyPrefix(keys[i])).Inc()
+		results[i] = []byte(s)
+	}
+
+	return results, nil
 }
 
-// 写入 Cache.
-func (c *Cache) Set(ctx context.Context, key string, value interface{}) error {
-	result, err := json.Marshal(value)
-	if err != nil {
-		return ErrMarshal
+// SetBytes -.
+func (c *Cache) SetBytes(ctx context.Context, key string, value []byte, ttl time.Duration) (err error) {
+	ctx, span := startSpan(ctx, "set", key)
+	defer func() { endSpan(span, err) }()
+
+	if ttl <= 0 {
+		ttl = c.expires
 	}
 
-	err = c.redis.Set(ctx, key, result, c.expires).Err()
+	err = c.redis.Set(ctx, key, value, ttl).Err()
 	if err != nil {
+		cacheErrors.WithLabelValues(backendRedis, "set", keyPrefix(key)).Inc()
+
 		return ErrStorage
 	}
 
@@ -63,11 +119,16 @@ func (c *Cache) Set(ctx context.Context, key string, value interface{}) error {
 }
 
 // 删除 Cache.
-func (c *Cache) Del(ctx context.Context, key string) error {
-	err := c.redis.Del(ctx, key).Err()
+func (c *Cache) Del(ctx context.Context, key string) (err error) {
+	ctx, span := startSpan(ctx, "del", key)
+	defer func() { endSpan(span, err) }()
+
+	err = c.redis.Del(ctx, key).Err()
 	if errors.Is(err, redis.Nil) {
 		return nil
 	} else if err != nil {
+		cacheErrors.WithLabelValues(backendRedis, "del", keyPrefix(key)).Inc()
+
 		return ErrStorage
 	}
 
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec 负责对象与缓存中字节之间的转换.
+// JSON 遵循 json 标签，json:"-" 的字段不会被缓存；Msgpack 和 Gob 会保留全部导出字段，适合缓存实体.
+// 注意 Msgpack 解码的 time.Time 为本地时区，需要保留时区时使用 Gob.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+//nolint:gochecknoglobals
+var (
+	JSON    Codec = jsonCodec{}
+	Msgpack Codec = msgpackCodec{}
+	Gob     Codec = gobCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// 在 Cacher 的字节接口之上使用 JSON 实现 Get，保持 Cacher.Get 原有的语义.
+func getJSON(ctx context.Context, c Cacher, backend, key string, target interface{}) error {
+	data, err := c.GetBytes(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, target); err != nil {
+		cacheErrors.WithLabelValues(backend, "get", keyPrefix(key)).Inc()
+
+		return ErrUnmarshal
+	}
+
+	return nil
+}
+
+// 在 Cacher 的字节接口之上使用 JSON 实现 Set，使用默认过期时间.
+func setJSON(ctx context.Context, c Cacher, backend, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		cacheErrors.WithLabelValues(backend, "set", keyPrefix(key)).Inc()
+
+		return ErrMarshal
+	}
+
+	return c.SetBytes(ctx, key, data, 0)
+}
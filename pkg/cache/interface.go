@@ -1,6 +1,9 @@
 package cache
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Cacher interface {
 	// 从 Cache 中获取对象
@@ -9,4 +12,16 @@ type Cacher interface {
 	Set(ctx context.Context, key string, value interface{}) error
 	// 删除 Cache
 	Del(ctx context.Context, key string) error
+
+	// 获取编码后的字节，不存在时返回 ErrMiss
+	GetBytes(ctx context.Context, key string) ([]byte, error)
+	// 批量获取编码后的字节，结果与 keys 一一对应，不存在的 key 为 nil
+	MGetBytes(ctx context.Context, keys []string) ([][]byte, error)
+	// 写入编码后的字节，ttl 为 0 时使用默认过期时间
+	SetBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Filler 由多级缓存实现：回填读穿透未命中的 key 时，其他实例的本地缓存中也没有这个 key，不需要广播失效.
+type Filler interface {
+	FillBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error
 }
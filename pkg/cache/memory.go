@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultMemorySize = 10000
+
+type memoryEntry struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// Memory 是进程内的 LRU 缓存，超过 size 个条目时淘汰最久未使用的条目.
+// 与 Redis 实现一致，对象以 JSON 形式存储，Get 得到的是副本，过期或不存在时返回 ErrMiss.
+type Memory struct {
+	size    int
+	expires time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func NewMemory(size int, expires time.Duration) *Memory {
+	if size <= 0 {
+		size = DefaultMemorySize
+	}
+
+	return &Memory{
+		size:    size,
+		expires: expires,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// 从 Cache 中获取对象.
+func (m *Memory) Get(ctx context.Context, key string, target interface{}) error {
+	return getJSON(ctx, m, backendMemory, key, target)
+}
+
+// 写入 Cache.
+func (m *Memory) Set(ctx context.Context, key string, value interface{}) error {
+	return setJSON(ctx, m, backendMemory, key, value)
+}
+
+// GetBytes -.
+func (m *Memory) GetBytes(_ context.Context, key string) ([]byte, error) {
+	value, ok := m.get(key)
+	if !ok {
+		cacheMisses.WithLabelValues(backendMemory, keyPrefix(key)).Inc()
+
+		return nil, ErrMiss
+	}
+
+	cacheHits.WithLabelValues(backendMemory, keyPrefix(key)).Inc()
+
+	return value, nil
+}
+
+// MGetBytes -.
+func (m *Memory) MGetBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	results := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		// 未命中时 results[i] 保持为 nil
+		results[i], _ = m.GetBytes(ctx, key)
+	}
+
+	return results, nil
+}
+
+// SetBytes - ttl 为 0 时使用默认过期时间.
+func (m *Memory) SetBytes(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = m.expires
+	}
+
+	m.set(key, append([]byte(nil), value...), ttl)
+
+	return nil
+}
+
+// 删除 Cache.
+func (m *Memory) Del(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.items[key]; ok {
+		m.removeElement(e)
+	}
+
+	return nil
+}
+
+// Purge 清空全部条目.
+func (m *Memory) Purge() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ll.Init()
+	m.items = make(map[string]*list.Element)
+}
+
+// Len 返回当前条目数，包括已过期但尚未被清理的条目.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+func (m *Memory) get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, _ := e.Value.(*memoryEntry)
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		m.removeElement(e)
+
+		return nil, false
+	}
+
+	m.ll.MoveToFront(e)
+
+	return append([]byte(nil), entry.value...), true
+}
+
+func (m *Memory) set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// ttl 为 0 代表永不过期，只依赖 LRU 淘汰
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	if e, ok := m.items[key]; ok {
+		entry, _ := e.Value.(*memoryEntry)
+		entry.value = value
+		entry.expireAt = expireAt
+		m.ll.MoveToFront(e)
+
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryEntry{key: key, value: value, expireAt: expireAt})
+
+	for m.ll.Len() > m.size {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+func (m *Memory) removeElement(e *list.Element) {
+	m.ll.Remove(e)
+
+	entry, _ := e.Value.(*memoryEntry)
+	delete(m.items, entry.key)
+}
@@ -0,0 +1,80 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+)
+
+type item struct {
+	Name string
+	Tags []string
+}
+
+func TestMemoryGetSetDel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := cache.NewMemory(10, time.Minute)
+
+	var got item
+	require.ErrorIs(t, m.Get(ctx, "k", &got), cache.ErrMiss)
+
+	want := item{Name: "a", Tags: []string{"x"}}
+	require.NoError(t, m.Set(ctx, "k", want))
+	require.NoError(t, m.Get(ctx, "k", &got))
+	require.Equal(t, want, got)
+
+	// 返回的是副本
+	got.Tags[0] = "y"
+	require.NoError(t, m.Get(ctx, "k", &got))
+	require.Equal(t, "x", got.Tags[0])
+
+	require.NoError(t, m.Del(ctx, "k"))
+	require.NoError(t, m.Del(ctx, "k"))
+	require.ErrorIs(t, m.Get(ctx, "k", &got), cache.ErrMiss)
+
+	require.ErrorIs(t, m.Set(ctx, "bad", make(chan int)), cache.ErrMarshal)
+}
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := cache.NewMemory(2, 0)
+
+	require.NoError(t, m.Set(ctx, "a", 1))
+	require.NoError(t, m.Set(ctx, "b", 2))
+
+	var v int
+	// 访问 a 之后 b 成为最久未使用的条目
+	require.NoError(t, m.Get(ctx, "a", &v))
+	require.NoError(t, m.Set(ctx, "c", 3))
+
+	require.Equal(t, 2, m.Len())
+	require.ErrorIs(t, m.Get(ctx, "b", &v), cache.ErrMiss)
+	require.NoError(t, m.Get(ctx, "a", &v))
+	require.Equal(t, 1, v)
+	require.NoError(t, m.Get(ctx, "c", &v))
+	require.Equal(t, 3, v)
+}
+
+func TestMemoryExpires(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := cache.NewMemory(10, 20*time.Millisecond)
+
+	require.NoError(t, m.Set(ctx, "k", 1))
+
+	var v int
+	require.NoError(t, m.Get(ctx, "k", &v))
+
+	time.Sleep(30 * time.Millisecond)
+	require.ErrorIs(t, m.Get(ctx, "k", &v), cache.ErrMiss)
+	require.Equal(t, 0, m.Len())
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 指标中的 backend label.
+const (
+	backendMemory = "memory"
+	backendRedis  = "redis"
+	backendTiered = "tiered"
+)
+
+//nolint:gochecknoglobals
+var (
+	cacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "Total number of cache hits by backend and key prefix.",
+	}, []string{"backend", "prefix"})
+
+	cacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "Total number of cache misses by backend and key prefix.",
+	}, []string{"backend", "prefix"})
+
+	cacheErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_errors_total",
+		Help: "Total number of cache errors by backend, operation and key prefix.",
+	}, []string{"backend", "operation", "prefix"})
+
+	busPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "cache_invalidation_published_total",
+		Help: "Total number of cache keys published to the invalidation bus.",
+	})
+
+	busReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "cache_invalidation_received_total",
+		Help: "Total number of cache keys evicted locally by invalidation messages from other replicas.",
+	})
+
+	busErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_invalidation_errors_total",
+		Help: "Total number of invalidation bus errors by operation.",
+	}, []string{"operation"})
+
+	busReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "cache_invalidation_reconnects_total",
+		Help: "Total number of invalidation bus resubscriptions after a disconnect.",
+	})
+)
+
+// 使用 key 最后一个 ":" 之前的部分作为 label，如 "cache:user:admin" 为 "cache:user"，避免 label 基数过高.
+// Tags.Key 追加的 "@<tag>:<generation>" 会先被去掉，如 "cache:users:query:<hash>@users:1" 为 "cache:users:query".
+func keyPrefix(key string) string {
+	if i := strings.IndexByte(key, '@'); i >= 0 {
+		key = key[:i]
+	}
+
+	i := strings.LastIndex(key, ":")
+	if i < 0 {
+		return ""
+	}
+
+	return key[:i]
+}
@@ -0,0 +1,49 @@
+package cache
+
+import "time"
+
+// Option -.
+type Option func(*ReadThrough)
+
+// TTL - 条目的逻辑过期时间.
+func TTL(ttl time.Duration) Option {
+	return func(rt *ReadThrough) {
+		rt.ttl = ttl
+	}
+}
+
+// Negative - 开启负缓存，is 判定为 true 的加载错误会缓存 ttl 时长.
+func Negative(ttl time.Duration, is func(error) bool) Option {
+	return func(rt *ReadThrough) {
+		rt.negativeTTL = ttl
+		rt.negative = is
+	}
+}
+
+// Jitter - TTL 的随机抖动比例，如 0.1 代表 TTL 在 ±10% 范围内浮动.
+func Jitter(ratio float64) Option {
+	return func(rt *ReadThrough) {
+		rt.jitter = ratio
+	}
+}
+
+// StaleTTL - 条目过期后仍可返回旧值的时长，期间会在后台刷新，0 代表关闭.
+func StaleTTL(ttl time.Duration) Option {
+	return func(rt *ReadThrough) {
+		rt.staleTTL = ttl
+	}
+}
+
+// LoadTimeout - 加载（包括后台刷新）的超时时间，加载不受发起请求的 ctx 取消影响.
+func LoadTimeout(timeout time.Duration) Option {
+	return func(rt *ReadThrough) {
+		rt.loadTimeout = timeout
+	}
+}
+
+// Encoding - 条目使用的编解码器，默认为 JSON.
+func Encoding(codec Codec) Option {
+	return func(rt *ReadThrough) {
+		rt.codec = codec
+	}
+}
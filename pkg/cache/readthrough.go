@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultReadThroughTTL = DefaultCacheExpires
+	defaultLoadTimeout    = 10 * time.Second
+)
+
+// ErrNegative 代表命中了负缓存，即之前加载时返回了 Negative 判定的错误（如 NotFound）.
+var ErrNegative = errors.New("cache negative hit")
+
+// LoadFunc 在缓存未命中时加载数据.
+type LoadFunc func(ctx context.Context) (interface{}, error)
+
+// 缓存中存储的条目，过期时间由 ReadThrough 自己维护（逻辑过期），
+// 写入底层 Cacher 时的过期时间为逻辑过期时间 + StaleTTL.
+type entry struct {
+	Value    []byte `json:"v,omitempty" msgpack:"v,omitempty"`
+	Negative bool   `json:"n,omitempty" msgpack:"n,omitempty"`
+	Err      string `json:"e,omitempty" msgpack:"e,omitempty"`
+	ExpireAt int64  `json:"x"           msgpack:"x"`
+}
+
+// ReadThrough 实现读穿透缓存：
+//   - 同一个 key 并发未命中时只会加载一次（singleflight）；
+//   - 可选的负缓存，Negative 判定的错误会以较短的 NegativeTTL 缓存；
+//   - TTL 增加随机抖动，避免大量 key 同时过期；
+//   - 可选的 stale-while-revalidate，过期后 StaleTTL 内先返回旧值，同时在后台刷新.
+type ReadThrough struct {
+	cache Cacher
+	codec Codec
+	group singleflight.Group
+
+	ttl         time.Duration
+	negativeTTL time.Duration
+	staleTTL    time.Duration
+	jitter      float64
+	negative    func(error) bool
+	loadTimeout time.Duration
+}
+
+func NewReadThrough(c Cacher, opts ...Option) *ReadThrough {
+	rt := &ReadThrough{
+		cache:       c,
+		codec:       JSON,
+		ttl:         DefaultReadThroughTTL,
+		loadTimeout: defaultLoadTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	return rt
+}
+
+// Get 从缓存中读取 key 到 target，未命中时调用 load 加载并写入缓存.
+// 缓存读写失败不影响结果，load 返回的错误原样返回，负缓存命中时返回包装了 ErrNegative 的错误.
+// 并发未命中的调用共享同一次加载，加载不受发起调用的 ctx 取消影响，超时时间为 LoadTimeout.
+func (rt *ReadThrough) Get(ctx context.Context, key string, target interface{}, load LoadFunc) error {
+	var e entry
+
+	data, err := rt.cache.GetBytes(ctx, key)
+	if err == nil && rt.codec.Unmarshal(data, &e) == nil {
+		now := time.Now().UnixMilli()
+
+		switch {
+		case now < e.ExpireAt:
+			return rt.decode(&e, target)
+		case rt.staleTTL > 0 && now < e.ExpireAt+rt.staleTTL.Milliseconds():
+			rt.refresh(ctx, key, load)
+
+			return rt.decode(&e, target)
+		}
+	}
+
+	// 缓存中没有这个 key 时是回填，不需要通知其他实例
+	fill := errors.Is(err, ErrMiss)
+
+	v, err, _ := rt.group.Do(key, func() (interface{}, error) {
+		return rt.detachedLoad(ctx, key, load, fill)
+	})
+	if err != nil {
+		return err
+	}
+
+	loaded, _ := v.(*entry)
+
+	return rt.decode(loaded, target)
+}
+
+// 在后台刷新过期的条目.
+func (rt *ReadThrough) refresh(ctx context.Context, key string, load LoadFunc) {
+	go func() {
+		_, _, _ = rt.group.Do(key, func() (interface{}, error) {
+			return rt.detachedLoad(ctx, key, load, false)
+		})
+	}()
+}
+
+// 使用新的 ctx 加载，避免发起加载的请求结束后加载被取消（*gin.Context 在请求结束后也会被复用），只保留当前的 trace span.
+func (rt *ReadThrough) detachedLoad(ctx context.Context, key string, load LoadFunc, fill bool) (*entry, error) {
+	ctx, cancel := context.WithTimeout(trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx)), rt.loadTimeout)
+	defer cancel()
+
+	return rt.load(ctx, key, load, fill)
+}
+
+func (rt *ReadThrough) load(ctx context.Context, key string, load LoadFunc, fill bool) (*entry, error) {
+	var e entry
+
+	v, err := load(ctx)
+
+	var ttl time.Duration
+
+	switch {
+	case err == nil:
+		e.Value, err = rt.codec.Marshal(v)
+		if err != nil {
+			return nil, ErrMarshal
+		}
+
+		ttl = rt.jittered(rt.ttl)
+	case rt.negative != nil && rt.negativeTTL > 0 && rt.negative(err):
+		e.Negative = true
+		e.Err = err.Error()
+		ttl = rt.jittered(rt.negativeTTL)
+	default:
+		return nil, err
+	}
+
+	e.ExpireAt = time.Now().Add(ttl).UnixMilli()
+
+	// 写缓存失败时仍然返回加载的结果，错误已经记录在指标中
+	if data, merr := rt.codec.Marshal(e); merr == nil {
+		rt.store(ctx, key, data, ttl+rt.staleTTL, fill)
+	}
+
+	// 负缓存时本次仍返回原始错误
+	return &e, err
+}
+
+// 回填时如果底层缓存支持 Filler 则不广播失效，覆盖旧值（如后台刷新）时使用 SetBytes.
+func (rt *ReadThrough) store(ctx context.Context, key string, data []byte, ttl time.Duration, fill bool) {
+	if f, ok := rt.cache.(Filler); ok && fill {
+		_ = f.FillBytes(ctx, key, data, ttl)
+
+		return
+	}
+
+	_ = rt.cache.SetBytes(ctx, key, data, ttl)
+}
+
+// 在 [-jitter, +jitter] 范围内随机调整 TTL.
+func (rt *ReadThrough) jittered(ttl time.Duration) time.Duration {
+	if rt.jitter > 0 {
+		ttl += time.Duration((rand.Float64()*2 - 1) * rt.jitter * float64(ttl)) //nolint:gosec
+	}
+
+	return ttl
+}
+
+func (rt *ReadThrough) decode(e *entry, target interface{}) error {
+	if e.Negative {
+		return fmt.Errorf("%w: %s", ErrNegative, e.Err)
+	}
+
+	if err := rt.codec.Unmarshal(e.Value, target); err != nil {
+		return ErrUnmarshal
+	}
+
+	return nil
+}
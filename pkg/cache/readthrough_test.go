@@ -0,0 +1,153 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestReadThroughNegative(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rt := cache.NewReadThrough(cache.NewMemory(10, time.Minute),
+		cache.Negative(time.Minute, func(err error) bool { return errors.Is(err, errNotFound) }),
+	)
+
+	var calls int32
+
+	load := func(context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return nil, errNotFound
+	}
+
+	var v int
+	require.ErrorIs(t, rt.Get(ctx, "k", &v, load), errNotFound)
+	require.ErrorIs(t, rt.Get(ctx, "k", &v, load), cache.ErrNegative)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 其他错误不会被缓存
+	errOther := errors.New("other")
+	require.ErrorIs(t, rt.Get(ctx, "o", &v, func(context.Context) (interface{}, error) { return nil, errOther }), errOther)
+	require.NoError(t, rt.Get(ctx, "o", &v, func(context.Context) (interface{}, error) { return 1, nil }))
+	require.Equal(t, 1, v)
+}
+
+func TestReadThroughStaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rt := cache.NewReadThrough(cache.NewMemory(10, time.Minute),
+		cache.TTL(20*time.Millisecond),
+		cache.StaleTTL(time.Minute),
+	)
+
+	var version int32
+
+	load := func(context.Context) (interface{}, error) {
+		return atomic.AddInt32(&version, 1), nil
+	}
+
+	var v int32
+	require.NoError(t, rt.Get(ctx, "k", &v, load))
+	require.Equal(t, int32(1), v)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// 过期后先返回旧值，并在后台刷新
+	require.NoError(t, rt.Get(ctx, "k", &v, load))
+	require.Equal(t, int32(1), v)
+
+	require.Eventually(t, func() bool {
+		return rt.Get(ctx, "k", &v, load) == nil && v == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestReadThroughExpired(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rt := cache.NewReadThrough(cache.NewMemory(10, time.Minute),
+		cache.TTL(20*time.Millisecond),
+		cache.Jitter(0.1),
+	)
+
+	var version int32
+
+	load := func(context.Context) (interface{}, error) {
+		return atomic.AddInt32(&version, 1), nil
+	}
+
+	var v int32
+	require.NoError(t, rt.Get(ctx, "k", &v, load))
+	require.NoError(t, rt.Get(ctx, "k", &v, load))
+	require.Equal(t, int32(1), v)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// 未开启 stale-while-revalidate 时同步重新加载
+	require.NoError(t, rt.Get(ctx, "k", &v, load))
+	require.Equal(t, int32(2), v)
+}
+
+func TestReadThroughCallerCanceled(t *testing.T) {
+	t.Parallel()
+
+	rt := cache.NewReadThrough(cache.NewMemory(10, time.Minute))
+
+	var calls int32
+
+	started, release := make(chan struct{}), make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+
+		// 发起加载的调用取消后加载仍然继续
+		return 1, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	err1 := make(chan error, 1)
+
+	var v1 int
+
+	go func() {
+		err1 <- rt.Get(ctx1, "k", &v1, load)
+	}()
+
+	<-started
+
+	err2 := make(chan error, 1)
+
+	var v2 int
+
+	go func() {
+		err2 <- rt.Get(context.Background(), "k", &v2, load)
+	}()
+
+	// 等待第二个调用加入同一次加载
+	time.Sleep(20 * time.Millisecond)
+	cancel1()
+	close(release)
+
+	require.NoError(t, <-err1)
+	require.NoError(t, <-err2)
+	require.Equal(t, 1, v1)
+	require.Equal(t, 1, v2)
+
+	// 加载结果已缓存
+	var v int
+	require.NoError(t, rt.Get(context.Background(), "k", &v, load))
+	require.Equal(t, 1, v)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
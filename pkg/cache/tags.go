@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTagTTL = 24 * time.Hour
+
+	tagKeyPrefix = "cache:tag:"
+)
+
+// Tags 实现基于代际（generation）的标签失效：
+// 缓存 key 中带上所属标签当前的代际，Invalidate 时更换代际，旧代际的 key 不会再被读取，等待自然过期.
+// 代际使用纳秒时间戳而不是自增计数，代际 key 过期或丢失后重新生成也不会与旧值重复.
+type Tags struct {
+	cache Cacher
+	ttl   time.Duration
+}
+
+// NewTags - ttl 为代际 key 的过期时间，需要大于使用标签的缓存条目的过期时间.
+func NewTags(c Cacher, ttl time.Duration) *Tags {
+	return &Tags{
+		cache: c,
+		ttl:   ttl,
+	}
+}
+
+// Key 返回带有标签代际的 key，如 "cache:users:query:<hash>@users:1700000000000000000".
+func (t *Tags) Key(ctx context.Context, key string, tags ...string) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(key)
+
+	for i, tag := range tags {
+		gen, err := t.generation(ctx, tag)
+		if err != nil {
+			return "", err
+		}
+
+		if i == 0 {
+			sb.WriteByte('@')
+		} else {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(tag)
+		sb.WriteByte(':')
+		sb.WriteString(gen)
+	}
+
+	return sb.String(), nil
+}
+
+// Invalidate 更换标签的代际，使用这些标签的缓存条目全部失效.
+func (t *Tags) Invalidate(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		if _, err := t.bump(ctx, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Tags) generation(ctx context.Context, tag string) (string, error) {
+	gen, err := t.cache.GetBytes(ctx, tagKeyPrefix+tag)
+	if errors.Is(err, ErrMiss) {
+		return t.bump(ctx, tag)
+	} else if err != nil {
+		return "", err
+	}
+
+	return string(gen), nil
+}
+
+func (t *Tags) bump(ctx context.Context, tag string) (string, error) {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	err := t.cache.SetBytes(ctx, tagKeyPrefix+tag, []byte(gen), t.ttl)
+	if err != nil {
+		return "", err
+	}
+
+	return gen, nil
+}
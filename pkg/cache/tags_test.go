@@ -0,0 +1,97 @@
+package cache_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := cache.NewMemory(100, time.Minute)
+	tags := cache.NewTags(c, time.Hour)
+
+	k1, err := tags.Key(ctx, "list:1", "users")
+	require.NoError(t, err)
+	k2, err := tags.Key(ctx, "list:2", "users", "roles")
+	require.NoError(t, err)
+
+	// 未失效时 key 保持不变
+	again, err := tags.Key(ctx, "list:1", "users")
+	require.NoError(t, err)
+	require.Equal(t, k1, again)
+
+	require.NoError(t, c.Set(ctx, k1, 1))
+	require.NoError(t, c.Set(ctx, k2, 2))
+
+	// 失效后使用新的 key，旧条目不会被读到
+	require.NoError(t, tags.Invalidate(ctx, "users"))
+
+	n1, err := tags.Key(ctx, "list:1", "users")
+	require.NoError(t, err)
+	require.NotEqual(t, k1, n1)
+
+	n2, err := tags.Key(ctx, "list:2", "users", "roles")
+	require.NoError(t, err)
+	require.NotEqual(t, k2, n2)
+
+	var v int
+	require.ErrorIs(t, c.Get(ctx, n1, &v), cache.ErrMiss)
+	require.ErrorIs(t, c.Get(ctx, n2, &v), cache.ErrMiss)
+
+	// 其他标签不受影响
+	r1, err := tags.Key(ctx, "list:3", "roles")
+	require.NoError(t, err)
+	r2, err := tags.Key(ctx, "list:3", "roles")
+	require.NoError(t, err)
+	require.Equal(t, r1, r2)
+}
+
+func TestTagsMetricsPrefix(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := cache.NewMemory(100, time.Minute)
+	tags := cache.NewTags(c, time.Hour)
+
+	key, err := tags.Key(ctx, "cache:users:query:"+strings.Repeat("a", 64), "users")
+	require.NoError(t, err)
+
+	_, err = c.GetBytes(ctx, key)
+	require.ErrorIs(t, err, cache.ErrMiss)
+
+	// label 中不包含查询的 hash 和标签的代际
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	var prefixes []string
+
+	for _, f := range families {
+		if f.GetName() != "cache_misses_total" {
+			continue
+		}
+
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "prefix" {
+					prefixes = append(prefixes, l.GetValue())
+				}
+			}
+		}
+	}
+
+	require.Contains(t, prefixes, "cache:users:query")
+
+	for _, p := range prefixes {
+		require.NotContains(t, p, "@")
+		require.NotContains(t, p, "aaaa")
+	}
+}
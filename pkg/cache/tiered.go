@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Tiered 是两级缓存：进程内 L1（通常是 TTL 较短的 Memory）+ 共享的 L2（通常是 Redis）.
+// 读取时先查 L1，未命中再查 L2 并回填 L1；写入和删除时先操作 L2 再操作 L1.
+// 多实例部署时通过 bus 广播写入和删除的 key，让其他实例删除各自的 L1；
+// bus 为 nil 或广播失败时，L1 的 TTL 决定了最长的脏读时间.
+type Tiered struct {
+	local    Cacher
+	remote   Cacher
+	bus      *Bus
+	localTTL time.Duration
+}
+
+var _ Filler = (*Tiered)(nil)
+
+// NewTiered - localTTL 为 L1 的过期时间上限，需要与 local 的默认过期时间一致.
+func NewTiered(local, remote Cacher, bus *Bus, localTTL time.Duration) *Tiered {
+	return &Tiered{
+		local:    local,
+		remote:   remote,
+		bus:      bus,
+		localTTL: localTTL,
+	}
+}
+
+// 从 Cache 中获取对象.
+func (t *Tiered) Get(ctx context.Context, key string, target interface{}) error {
+	return getJSON(ctx, t, backendTiered, key, target)
+}
+
+// 写入 Cache.
+func (t *Tiered) Set(ctx context.Context, key string, value interface{}) error {
+	return setJSON(ctx, t, backendTiered, key, value)
+}
+
+// GetBytes - 先查 L1，未命中再查 L2 并回填 L1.
+func (t *Tiered) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	value, err := t.local.GetBytes(ctx, key)
+	if !errors.Is(err, ErrMiss) {
+		return value, err
+	}
+
+	value, err = t.remote.GetBytes(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// 回填失败不影响本次读取
+	_ = t.local.SetBytes(ctx, key, value, 0)
+
+	return value, nil
+}
+
+// MGetBytes - 只有 L1 未命中的 key 才会查询 L2.
+func (t *Tiered) MGetBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	results, err := t.local.MGetBytes(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		missKeys []string
+		missIdx  []int
+	)
+
+	for i, v := range results {
+		if v == nil {
+			missKeys = append(missKeys, keys[i])
+			missIdx = append(missIdx, i)
+		}
+	}
+
+	if len(missKeys) == 0 {
+		return results, nil
+	}
+
+	remote, err := t.remote.MGetBytes(ctx, missKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range remote {
+		if v == nil {
+			continue
+		}
+
+		results[missIdx[i]] = v
+		_ = t.local.SetBytes(ctx, missKeys[i], v, 0)
+	}
+
+	return results, nil
+}
+
+// SetBytes - 写入 L1 和 L2，并通知其他实例删除 L1 中的旧值.
+func (t *Tiered) SetBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := t.FillBytes(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	return t.publish(ctx, key)
+}
+
+// FillBytes - 写入 L1 和 L2，不通知其他实例；L1 的过期时间不超过 ttl 和 localTTL.
+func (t *Tiered) FillBytes(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := t.remote.SetBytes(ctx, key, value, ttl)
+	if err != nil {
+		return err
+	}
+
+	localTTL := ttl
+	if localTTL <= 0 || localTTL > t.localTTL {
+		localTTL = t.localTTL
+	}
+
+	return t.local.SetBytes(ctx, key, value, localTTL)
+}
+
+// 删除 Cache，L2 删除失败时也要删除 L1，避免本实例继续读到旧值.
+func (t *Tiered) Del(ctx context.Context, key string) error {
+	err := t.remote.Del(ctx, key)
+
+	if lerr := t.local.Del(ctx, key); err == nil {
+		err = lerr
+	}
+
+	if perr := t.publish(ctx, key); err == nil {
+		err = perr
+	}
+
+	return err
+}
+
+func (t *Tiered) publish(ctx context.Context, key string) error {
+	if t.bus == nil {
+		return nil
+	}
+
+	return t.bus.Publish(ctx, key)
+}
@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+)
+
+func TestTiered(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	local := cache.NewMemory(10, time.Minute)
+	remote := cache.NewMemory(10, time.Minute)
+	c := cache.NewTiered(local, remote, nil, time.Minute)
+
+	var v int
+	require.ErrorIs(t, c.Get(ctx, "k", &v), cache.ErrMiss)
+
+	// 写入同时写 L1 和 L2
+	require.NoError(t, c.Set(ctx, "k", 1))
+	require.NoError(t, local.Get(ctx, "k", &v))
+	require.NoError(t, remote.Get(ctx, "k", &v))
+
+	// L1 未命中时从 L2 读取并回填
+	require.NoError(t, remote.Set(ctx, "r", 2))
+	require.NoError(t, c.Get(ctx, "r", &v))
+	require.Equal(t, 2, v)
+	require.NoError(t, local.Get(ctx, "r", &v))
+	require.Equal(t, 2, v)
+
+	// L1 命中时不访问 L2
+	require.NoError(t, remote.Set(ctx, "r", 3))
+	require.NoError(t, c.Get(ctx, "r", &v))
+	require.Equal(t, 2, v)
+
+	require.NoError(t, c.Del(ctx, "r"))
+	require.ErrorIs(t, local.Get(ctx, "r", &v), cache.ErrMiss)
+	require.ErrorIs(t, remote.Get(ctx, "r", &v), cache.ErrMiss)
+}
+
+func TestTieredLocalTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	local := cache.NewMemory(10, time.Hour)
+	remote := cache.NewMemory(10, time.Hour)
+	c := cache.NewTiered(local, remote, nil, 50*time.Millisecond)
+
+	// L1 的过期时间不超过写入的 ttl，也不超过 localTTL
+	require.NoError(t, c.SetBytes(ctx, "short", []byte("1"), 10*time.Millisecond))
+	require.NoError(t, c.SetBytes(ctx, "long", []byte("1"), time.Hour))
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := local.GetBytes(ctx, "short")
+	require.ErrorIs(t, err, cache.ErrMiss)
+	_, err = local.GetBytes(ctx, "long")
+	require.NoError(t, err)
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, err = local.GetBytes(ctx, "long")
+	require.ErrorIs(t, err, cache.ErrMiss)
+	_, err = remote.GetBytes(ctx, "long")
+	require.NoError(t, err)
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ninehills/go-webapp-template/pkg/cache"
+
+func startSpan(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "cache "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemRedis,
+			semconv.DBOperation(operation),
+			attribute.String("cache.key", key),
+		),
+	)
+}
+
+// 结束 span，未命中不算作错误，只记录 cache.hit=false.
+func endSpan(span trace.Span, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrMiss):
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
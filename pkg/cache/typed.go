@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Typed 是带类型的缓存，使用指定的 Codec 编解码 T，避免 interface{} 参数和 JSON 标签带来的字段丢失.
+type Typed[T any] struct {
+	cache Cacher
+	codec Codec
+	ttl   time.Duration
+	rt    *ReadThrough
+}
+
+// NewTyped - ttl 为 0 时使用底层 Cacher 的默认过期时间，Load 使用 DefaultReadThroughTTL；
+// opts 用于配置 Load 的读穿透行为，如负缓存、抖动等.
+func NewTyped[T any](c Cacher, codec Codec, ttl time.Duration, opts ...Option) *Typed[T] {
+	rtTTL := ttl
+	if rtTTL <= 0 {
+		rtTTL = DefaultReadThroughTTL
+	}
+
+	return &Typed[T]{
+		cache: c,
+		codec: codec,
+		ttl:   ttl,
+		rt:    NewReadThrough(c, append([]Option{TTL(rtTTL), Encoding(codec)}, opts...)...),
+	}
+}
+
+// Get 获取 key 对应的对象，不存在时返回 ErrMiss.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+
+	data, err := t.cache.GetBytes(ctx, key)
+	if err != nil {
+		return v, err
+	}
+
+	if err = t.codec.Unmarshal(data, &v); err != nil {
+		return v, ErrUnmarshal
+	}
+
+	return v, nil
+}
+
+// MGet 批量获取，只返回命中的 key；任一对象解码失败时返回 ErrUnmarshal.
+func (t *Typed[T]) MGet(ctx context.Context, keys ...string) (map[string]T, error) {
+	values, err := t.cache.MGetBytes(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]T, len(keys))
+
+	for i, data := range values {
+		if data == nil {
+			continue
+		}
+
+		var v T
+		if err = t.codec.Unmarshal(data, &v); err != nil {
+			return nil, ErrUnmarshal
+		}
+
+		result[keys[i]] = v
+	}
+
+	return result, nil
+}
+
+// Set 写入对象，ttl 为 0 时使用 Typed 的默认过期时间.
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		return ErrMarshal
+	}
+
+	if ttl <= 0 {
+		ttl = t.ttl
+	}
+
+	return t.cache.SetBytes(ctx, key, data, ttl)
+}
+
+// Del -.
+func (t *Typed[T]) Del(ctx context.Context, key string) error {
+	return t.cache.Del(ctx, key)
+}
+
+// Load 读穿透获取 key 对应的对象，未命中时调用 load 加载并写入缓存，语义同 ReadThrough.Get.
+// Load 写入的条目带有逻辑过期时间，同一个 key 不能与 Get / Set 混用.
+func (t *Typed[T]) Load(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var v T
+
+	err := t.rt.Get(ctx, key, &v, func(ctx context.Context) (interface{}, error) {
+		return load(ctx)
+	})
+
+	return v, err
+}
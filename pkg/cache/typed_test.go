@@ -0,0 +1,127 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/cache"
+)
+
+type account struct {
+	ID        int64  `json:"-"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+}
+
+func TestTypedCodecs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	want := account{ID: 42, Name: "alice", CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	for name, codec := range map[string]cache.Codec{"msgpack": cache.Msgpack, "gob": cache.Gob} {
+		c := cache.NewTyped[account](cache.NewMemory(10, time.Minute), codec, 0)
+
+		require.NoError(t, c.Set(ctx, "k", want, 0), name)
+
+		got, err := c.Get(ctx, "k")
+		require.NoError(t, err, name)
+		require.Equal(t, want.ID, got.ID, name)
+		require.Equal(t, want.Name, got.Name, name)
+		require.True(t, want.CreatedAt.Equal(got.CreatedAt), name)
+	}
+
+	// JSON 遵循 json 标签，ID 不会被缓存
+	c := cache.NewTyped[account](cache.NewMemory(10, time.Minute), cache.JSON, 0)
+	require.NoError(t, c.Set(ctx, "k", want, 0))
+
+	got, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	require.Zero(t, got.ID)
+	require.Equal(t, want.Name, got.Name)
+}
+
+func TestTypedMGetAndTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := cache.NewTyped[int](cache.NewMemory(10, time.Minute), cache.Msgpack, 0)
+
+	_, err := c.Get(ctx, "a")
+	require.ErrorIs(t, err, cache.ErrMiss)
+
+	require.NoError(t, c.Set(ctx, "a", 1, 0))
+	require.NoError(t, c.Set(ctx, "b", 2, 20*time.Millisecond))
+
+	got, err := c.MGet(ctx, "a", "b", "c")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, got)
+
+	// 单次写入的 TTL 覆盖默认过期时间
+	time.Sleep(30 * time.Millisecond)
+
+	got, err = c.MGet(ctx, "a", "b")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 1}, got)
+}
+
+func TestTypedRedis(t *testing.T) {
+	t.Parallel()
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx := context.Background()
+	c := cache.NewTyped[account](cache.NewCache(rdb, time.Minute), cache.Gob, 0)
+
+	require.NoError(t, c.Set(ctx, "a", account{ID: 1}, 0))
+	require.NoError(t, c.Set(ctx, "b", account{ID: 2}, time.Hour))
+	require.Equal(t, time.Minute, m.TTL("a"))
+	require.Equal(t, time.Hour, m.TTL("b"))
+
+	got, err := c.MGet(ctx, "a", "missing", "b")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, int64(1), got["a"].ID)
+	require.Equal(t, int64(2), got["b"].ID)
+}
+
+func TestTypedLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := cache.NewTyped[account](cache.NewMemory(10, time.Minute), cache.Msgpack, time.Minute,
+		cache.Negative(time.Minute, func(err error) bool { return errors.Is(err, errNotFound) }),
+	)
+
+	var calls int32
+
+	load := func(context.Context) (account, error) {
+		atomic.AddInt32(&calls, 1)
+
+		return account{ID: 1, Name: "alice"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := c.Load(ctx, "a", load)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), got.ID)
+	}
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 负缓存
+	_, err := c.Load(ctx, "b", func(context.Context) (account, error) { return account{}, errNotFound })
+	require.ErrorIs(t, err, errNotFound)
+
+	_, err = c.Load(ctx, "b", load)
+	require.ErrorIs(t, err, cache.ErrNegative)
+}
@@ -0,0 +1,176 @@
+// Package health implements liveness and readiness checks.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTimeout  = 2 * time.Second
+	defaultCacheTTL = 2 * time.Second
+
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// CheckFunc 检查某个组件是否可用，返回 nil 代表可用.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult 是单个检查的结果.
+type CheckResult struct {
+	Status   string `example:"ok"                json:"status"`
+	Error    string `example:"connection refused" json:"error,omitempty"`
+	Duration string `example:"1.2ms"             json:"duration"`
+}
+
+// Result 是全部检查的结果.
+type Result struct {
+	Status    string                 `example:"ok"                   json:"status"`
+	Checks    map[string]CheckResult `json:"checks,omitempty"`
+	CheckedAt time.Time              `example:"2020-01-01T00:00:00Z" json:"checkedAt"`
+}
+
+// OK -.
+func (r Result) OK() bool {
+	return r.Status == StatusOK
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker 汇总所有组件的检查.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	checks   []check
+	cached   Result
+	cachedAt time.Time
+
+	shuttingDown atomic.Bool
+}
+
+// New -.
+func New(opts ...Option) *Checker {
+	c := &Checker{
+		timeout:  defaultTimeout,
+		cacheTTL: defaultCacheTTL,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Register 注册一个就绪检查.
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, check{name: name, fn: fn})
+	c.cachedAt = time.Time{}
+}
+
+// Shutdown 标记服务正在关闭，之后 Ready 会立即返回失败.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Live 只代表进程存活，不检查任何依赖，避免依赖故障时被重启.
+func (c *Checker) Live() Result {
+	return Result{Status: StatusOK, CheckedAt: time.Now()}
+}
+
+// Ready 执行全部检查（带缓存），任意一个失败或正在关闭时返回失败.
+// 检查不使用请求的 context，避免探针断开连接后缓存一个失败的结果.
+func (c *Checker) Ready() Result {
+	if c.shuttingDown.Load() {
+		return Result{
+			Status:    StatusFail,
+			Checks:    map[string]CheckResult{"shutdown": {Status: StatusFail, Error: ErrShuttingDown.Error()}},
+			CheckedAt: time.Now(),
+		}
+	}
+
+	// 持有锁执行检查，并发的探针请求会等待同一次检查的结果
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.cachedAt.IsZero() && time.Since(c.cachedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	c.cached = c.run(context.Background())
+	c.cachedAt = time.Now()
+
+	return c.cached
+}
+
+func (c *Checker) run(ctx context.Context) Result {
+	var (
+		wg      sync.WaitGroup
+		results = make([]CheckResult, len(c.checks))
+	)
+
+	for i, ck := range c.checks {
+		wg.Add(1)
+
+		go func(i int, ck check) {
+			defer wg.Done()
+
+			results[i] = c.runOne(ctx, ck)
+		}(i, ck)
+	}
+
+	wg.Wait()
+
+	r := Result{Status: StatusOK, Checks: map[string]CheckResult{}, CheckedAt: time.Now()}
+
+	for i, ck := range c.checks {
+		r.Checks[ck.name] = results[i]
+		if results[i].Status != StatusOK {
+			r.Status = StatusFail
+		}
+	}
+
+	return r
+}
+
+func (c *Checker) runOne(ctx context.Context, ck check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- ck.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	r := CheckResult{Status: StatusOK, Duration: time.Since(started).String()}
+	if err != nil {
+		r.Status = StatusFail
+		r.Error = err.Error()
+	}
+
+	return r
+}
@@ -0,0 +1,60 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/health"
+)
+
+func TestReady(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+
+	c := health.New(health.Timeout(50*time.Millisecond), health.CacheTTL(time.Hour))
+	c.Register("ok", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+
+		return nil
+	})
+
+	r := c.Ready()
+	require.True(t, r.OK())
+	require.Equal(t, health.StatusOK, r.Checks["ok"].Status)
+
+	// 命中缓存
+	c.Ready()
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	c.Shutdown()
+	r = c.Ready()
+	require.False(t, r.OK())
+	require.True(t, c.Live().OK())
+}
+
+func TestReadyFailure(t *testing.T) {
+	t.Parallel()
+
+	c := health.New(health.Timeout(50*time.Millisecond), health.CacheTTL(0))
+	c.Register("ok", func(ctx context.Context) error { return nil })
+	c.Register("broken", func(ctx context.Context) error { return errors.New("connection refused") })
+	c.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	})
+
+	started := time.Now()
+	r := c.Ready()
+	require.Less(t, time.Since(started), 500*time.Millisecond)
+	require.False(t, r.OK())
+	require.Equal(t, health.StatusOK, r.Checks["ok"].Status)
+	require.Equal(t, "connection refused", r.Checks["broken"].Error)
+	require.Equal(t, context.DeadlineExceeded.Error(), r.Checks["slow"].Error)
+}
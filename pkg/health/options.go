@@ -0,0 +1,20 @@
+package health
+
+import "time"
+
+// Option -.
+type Option func(*Checker)
+
+// Timeout - 单个检查的超时时间.
+func Timeout(timeout time.Duration) Option {
+	return func(c *Checker) {
+		c.timeout = timeout
+	}
+}
+
+// CacheTTL - 检查结果的缓存时间，避免探针频繁访问数据库，0 代表不缓存.
+func CacheTTL(ttl time.Duration) Option {
+	return func(c *Checker) {
+		c.cacheTTL = ttl
+	}
+}
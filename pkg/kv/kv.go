@@ -0,0 +1,23 @@
+// Package kv implements a small key-value store with expiration on Redis, with an in-memory implementation for single instance deployments.
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("kv key not found")
+	ErrStorage  = errors.New("kv storage visit error")
+)
+
+// Store 保存带过期时间的键值，不存在或过期的 key 返回 ErrNotFound.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// 读取并删除 key，并发调用时只有一个调用者能取到值，其他调用者返回 ErrNotFound
+	Take(ctx context.Context, key string) ([]byte, error)
+	// 删除 key，不存在时不返回错误
+	Del(ctx context.Context, key string) error
+}
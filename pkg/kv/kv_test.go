@@ -0,0 +1,106 @@
+package kv_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/kv"
+)
+
+func stores(t *testing.T) map[string]kv.Store {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return map[string]kv.Store{
+		"redis":  kv.NewRedis(rdb),
+		"memory": kv.NewMemory(),
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for name, s := range stores(t) {
+		_, err := s.Get(ctx, "k1")
+		require.ErrorIs(t, err, kv.ErrNotFound, name)
+
+		require.NoError(t, s.Set(ctx, "k1", []byte("v1"), time.Minute), name)
+
+		value, err := s.Get(ctx, "k1")
+		require.NoError(t, err, name)
+		require.Equal(t, []byte("v1"), value, name)
+
+		// Take 之后不再存在
+		value, err = s.Take(ctx, "k1")
+		require.NoError(t, err, name)
+		require.Equal(t, []byte("v1"), value, name)
+
+		_, err = s.Take(ctx, "k1")
+		require.ErrorIs(t, err, kv.ErrNotFound, name)
+
+		require.NoError(t, s.Set(ctx, "k2", []byte("v2"), time.Minute), name)
+		require.NoError(t, s.Del(ctx, "k2"), name)
+		require.NoError(t, s.Del(ctx, "k2"), name)
+
+		_, err = s.Get(ctx, "k2")
+		require.ErrorIs(t, err, kv.ErrNotFound, name)
+	}
+}
+
+func TestStoreTakeOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for name, s := range stores(t) {
+		require.NoError(t, s.Set(ctx, "once", []byte("v"), time.Minute), name)
+
+		var (
+			wg    sync.WaitGroup
+			taken int32
+		)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if _, err := s.Take(ctx, "once"); err == nil {
+					atomic.AddInt32(&taken, 1)
+				}
+			}()
+		}
+
+		wg.Wait()
+		require.Equal(t, int32(1), taken, name)
+	}
+}
+
+func TestMemoryExpire(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := kv.NewMemory()
+
+	require.NoError(t, s.Set(ctx, "k", []byte("v"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := s.Get(ctx, "k")
+	require.ErrorIs(t, err, kv.ErrNotFound)
+
+	_, err = s.Take(ctx, "k")
+	require.ErrorIs(t, err, kv.ErrNotFound)
+}
@@ -0,0 +1,112 @@
+package kv
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 清理过期 key 的间隔，避免从未读取的 key 无限增长.
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	value    []byte
+	expireAt time.Time
+}
+
+// Memory 进程内的键值存储，不在实例之间共享，只用于单实例部署（如 SQLite 模式）.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemory -.
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get -.
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return e.value, nil
+}
+
+// Set -.
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	// 保存副本，调用者之后修改 value 不影响已保存的值
+	m.entries[key] = memoryEntry{value: append([]byte(nil), value...), expireAt: now.Add(ttl)}
+
+	return nil
+}
+
+// Take -.
+func (m *Memory) Take(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	delete(m.entries, key)
+
+	return e.value, nil
+}
+
+// Del -.
+func (m *Memory) Del(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+func (m *Memory) get(key string) (memoryEntry, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return memoryEntry{}, false
+	}
+
+	if !m.now().Before(e.expireAt) {
+		delete(m.entries, key)
+
+		return memoryEntry{}, false
+	}
+
+	return e, true
+}
+
+func (m *Memory) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+
+	for key, e := range m.entries {
+		if !now.Before(e.expireAt) {
+			delete(m.entries, key)
+		}
+	}
+
+	m.nextSweep = now.Add(memorySweepInterval)
+}
@@ -0,0 +1,69 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis 在 Redis 中保存键值，多实例共享.
+type Redis struct {
+	rdb *redis.Client
+}
+
+// NewRedis -.
+func NewRedis(rdb *redis.Client) *Redis {
+	return &Redis{rdb: rdb}
+}
+
+// Get -.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	return value, nil
+}
+
+// Set -.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.rdb.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	return nil
+}
+
+// Take 先读取再删除，只有删除成功的调用者返回值，不依赖 Redis 6.2 的 GETDEL.
+func (r *Redis) Take(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := r.rdb.Del(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	if n == 0 {
+		return nil, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// Del -.
+func (r *Redis) Del(ctx context.Context, key string) error {
+	if err := r.rdb.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	return nil
+}
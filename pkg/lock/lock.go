@@ -0,0 +1,248 @@
+// Package lock implements a Redis based distributed lock with lease extension and fencing tokens,
+// with an in-memory implementation for single instance deployments.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPrefix        = "lock:"
+	defaultRetryInterval = 100 * time.Millisecond
+	// 在 ttl 的 1/3 处续期，留出两次重试的余量
+	extendDivisor = 3
+)
+
+var (
+	ErrNotAcquired = errors.New("lock not acquired")
+	ErrNotHeld     = errors.New("lock not held")
+)
+
+//nolint:gochecknoglobals
+var (
+	// KEYS[1] 锁，KEYS[2] fencing token 计数器；ARGV[1] 持有者，ARGV[2] ttl(ms).
+	// 加锁成功时返回递增的 fencing token，失败返回 0.
+	acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+	// 只有持有者才能续期.
+	extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// compare-and-delete，只有持有者才能释放，避免锁过期后误删其他人的锁.
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// 锁的存储，持有者不一致时 extend 和 release 返回 false.
+type backend interface {
+	// 加锁成功时返回递增的 fencing token，失败返回 0
+	acquire(ctx context.Context, key, fenceKey, owner string, ttl time.Duration) (int64, error)
+	extend(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	release(ctx context.Context, key, owner string) (bool, error)
+}
+
+// Locker 创建分布式锁.
+type Locker struct {
+	backend       backend
+	prefix        string
+	retryInterval time.Duration
+}
+
+// New -.
+func New(rdb *redis.Client, opts ...Option) *Locker {
+	return newLocker(redisBackend{rdb: rdb}, opts...)
+}
+
+// NewMemory 创建进程内的锁，只在同一个进程内互斥，用于未配置 Redis 的单实例部署.
+func NewMemory(opts ...Option) *Locker {
+	return newLocker(newMemoryBackend(), opts...)
+}
+
+func newLocker(b backend, opts ...Option) *Locker {
+	lk := &Locker{
+		backend:       b,
+		prefix:        defaultPrefix,
+		retryInterval: defaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(lk)
+	}
+
+	return lk
+}
+
+// Acquire 获取锁，锁被占用时每隔 retryInterval 重试，直到 ctx 结束后返回 ErrNotAcquired.
+// 获取成功后会在后台自动续期，直到 Release；续期失败时 Lost() 会被关闭.
+func (lk *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	for {
+		l, err := lk.TryAcquire(ctx, key, ttl)
+		if err != nil && ctx.Err() != nil {
+			// ctx 在请求 Redis 时结束，与等待超时相同
+			return nil, fmt.Errorf("%w: %s: %v", ErrNotAcquired, key, ctx.Err())
+		}
+
+		if !errors.Is(err, ErrNotAcquired) {
+			return l, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %s: %v", ErrNotAcquired, key, ctx.Err())
+		case <-time.After(lk.retryInterval):
+		}
+	}
+}
+
+// TryAcquire 尝试获取一次锁，锁被占用时立即返回 ErrNotAcquired.
+func (lk *Locker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	owner := uuid.NewString()
+	lockKey := lk.prefix + key
+
+	token, err := lk.backend.acquire(ctx, lockKey, lk.prefix+"fence:"+key, owner, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("lock - acquire %s failed: %w", key, err)
+	}
+
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	l := &Lock{
+		backend: lk.backend,
+		key:     lockKey,
+		owner:   owner,
+		token:   token,
+		ttl:     ttl,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		lost:    make(chan struct{}),
+	}
+
+	go l.keepAlive()
+
+	return l, nil
+}
+
+// Lock 是已经获取到的锁.
+type Lock struct {
+	backend backend
+	key     string
+	owner   string
+	token   int64
+	ttl     time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+	lost chan struct{}
+	once sync.Once
+}
+
+// Token 返回 fencing token，同一个 key 每次加锁成功时单调递增.
+// 写入受保护的资源时带上 token，资源方拒绝比已见过的 token 更小的写入，即可防止锁过期后旧持有者的写入.
+func (l *Lock) Token() int64 {
+	return l.token
+}
+
+// Lost 在续期失败（锁已过期或被他人持有）时关闭，持有者应停止操作受保护的资源.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Extend 手动续期为 ttl.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	held, err := l.backend.extend(ctx, l.key, l.owner, ttl)
+	if err != nil {
+		return fmt.Errorf("lock - extend %s failed: %w", l.key, err)
+	}
+
+	if !held {
+		return ErrNotHeld
+	}
+
+	return nil
+}
+
+// Release 停止续期并释放锁，锁已经不再持有时返回 ErrNotHeld.
+func (l *Lock) Release(ctx context.Context) error {
+	l.once.Do(func() {
+		close(l.stop)
+		<-l.done
+	})
+
+	held, err := l.backend.release(ctx, l.key, l.owner)
+	if err != nil {
+		return fmt.Errorf("lock - release %s failed: %w", l.key, err)
+	}
+
+	if !held {
+		return ErrNotHeld
+	}
+
+	return nil
+}
+
+func (l *Lock) keepAlive() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.ttl / extendDivisor)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), l.ttl/extendDivisor)
+			err := l.Extend(ctx, l.ttl)
+			cancel()
+
+			// 网络错误时在下一个周期重试，锁已经不再持有时放弃
+			if errors.Is(err, ErrNotHeld) {
+				close(l.lost)
+
+				return
+			}
+		}
+	}
+}
+
+type redisBackend struct {
+	rdb *redis.Client
+}
+
+func (b redisBackend) acquire(ctx context.Context, key, fenceKey, owner string, ttl time.Duration) (int64, error) {
+	return acquireScript.Run(ctx, b.rdb, []string{key, fenceKey}, owner, ttl.Milliseconds()).Int64()
+}
+
+func (b redisBackend) extend(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	n, err := extendScript.Run(ctx, b.rdb, []string{key}, owner, ttl.Milliseconds()).Int64()
+
+	return n == 1, err
+}
+
+func (b redisBackend) release(ctx context.Context, key, owner string) (bool, error) {
+	n, err := releaseScript.Run(ctx, b.rdb, []string{key}, owner).Int64()
+
+	return n == 1, err
+}
@@ -0,0 +1,143 @@
+package lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/lock"
+)
+
+func newLocker(t *testing.T) (*lock.Locker, *miniredis.Miniredis) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return lock.New(rdb, lock.RetryInterval(5*time.Millisecond)), m
+}
+
+func TestAcquireRelease(t *testing.T) {
+	t.Parallel()
+
+	lk, _ := newLocker(t)
+	ctx := context.Background()
+
+	l1, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	_, err = lk.TryAcquire(ctx, "job", time.Second)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	// 等待超时
+	waitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+
+	_, err = lk.Acquire(waitCtx, "job", time.Second)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	require.NoError(t, l1.Release(ctx))
+	require.ErrorIs(t, l1.Release(ctx), lock.ErrNotHeld)
+
+	// fencing token 单调递增
+	l2, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+	require.Greater(t, l2.Token(), l1.Token())
+	require.NoError(t, l2.Release(ctx))
+}
+
+func TestAcquireWaitsForRelease(t *testing.T) {
+	t.Parallel()
+
+	lk, _ := newLocker(t)
+	ctx := context.Background()
+
+	l1, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = l1.Release(ctx)
+	}()
+
+	l2, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, l2.Release(ctx))
+}
+
+func TestLeaseExtension(t *testing.T) {
+	t.Parallel()
+
+	lk, m := newLocker(t)
+	ctx := context.Background()
+
+	l, err := lk.Acquire(ctx, "job", 300*time.Millisecond)
+	require.NoError(t, err)
+
+	// miniredis 不会自动流逝时间，手动快进并等待续期
+	for i := 0; i < 3; i++ {
+		time.Sleep(150 * time.Millisecond)
+		m.FastForward(150 * time.Millisecond)
+	}
+
+	require.True(t, m.Exists("lock:job"))
+
+	select {
+	case <-l.Lost():
+		t.Fatal("lock should not be lost")
+	default:
+	}
+
+	// 锁被删除（如过期）后续期失败，Lost 被关闭，Release 不会删除别人的锁
+	m.Del("lock:job")
+
+	other, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lock should be lost")
+	}
+
+	require.ErrorIs(t, l.Release(ctx), lock.ErrNotHeld)
+	require.True(t, m.Exists("lock:job"))
+	require.NoError(t, other.Release(ctx))
+}
+
+func TestMemoryLocker(t *testing.T) {
+	t.Parallel()
+
+	lk := lock.NewMemory(lock.RetryInterval(5 * time.Millisecond))
+	ctx := context.Background()
+
+	l1, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+
+	_, err = lk.TryAcquire(ctx, "job", time.Second)
+	require.ErrorIs(t, err, lock.ErrNotAcquired)
+
+	require.NoError(t, l1.Release(ctx))
+	require.ErrorIs(t, l1.Release(ctx), lock.ErrNotHeld)
+
+	l2, err := lk.Acquire(ctx, "job", time.Second)
+	require.NoError(t, err)
+	require.Greater(t, l2.Token(), l1.Token())
+	require.NoError(t, l2.Release(ctx))
+
+	// 过期的锁可以被他人获取，原持有者无法释放
+	l3, err := lk.TryAcquire(ctx, "expire", 30*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, l3.Extend(ctx, 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	l4, err := lk.TryAcquire(ctx, "expire", time.Second)
+	require.NoError(t, err)
+	require.ErrorIs(t, l3.Release(ctx), lock.ErrNotHeld)
+	require.NoError(t, l4.Release(ctx))
+}
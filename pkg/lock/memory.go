@@ -0,0 +1,73 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryLock struct {
+	owner    string
+	expireAt time.Time
+}
+
+// 进程内的锁，过期的锁在下次加锁时被覆盖.
+type memoryBackend struct {
+	mu     sync.Mutex
+	locks  map[string]memoryLock
+	fences map[string]int64
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		locks:  make(map[string]memoryLock),
+		fences: make(map[string]int64),
+	}
+}
+
+func (b *memoryBackend) acquire(_ context.Context, key, fenceKey, owner string, ttl time.Duration) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if l, ok := b.locks[key]; ok && now.Before(l.expireAt) {
+		return 0, nil
+	}
+
+	b.locks[key] = memoryLock{owner: owner, expireAt: now.Add(ttl)}
+	b.fences[fenceKey]++
+
+	return b.fences[fenceKey], nil
+}
+
+func (b *memoryBackend) extend(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.held(key, owner) {
+		return false, nil
+	}
+
+	b.locks[key] = memoryLock{owner: owner, expireAt: time.Now().Add(ttl)}
+
+	return true, nil
+}
+
+func (b *memoryBackend) release(_ context.Context, key, owner string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.held(key, owner) {
+		return false, nil
+	}
+
+	delete(b.locks, key)
+
+	return true, nil
+}
+
+func (b *memoryBackend) held(key, owner string) bool {
+	l, ok := b.locks[key]
+
+	return ok && l.owner == owner && time.Now().Before(l.expireAt)
+}
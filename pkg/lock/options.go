@@ -0,0 +1,20 @@
+package lock
+
+import "time"
+
+// Option -.
+type Option func(*Locker)
+
+// Prefix - 锁在 Redis 中的 key 前缀，默认为 "lock:".
+func Prefix(prefix string) Option {
+	return func(lk *Locker) {
+		lk.prefix = prefix
+	}
+}
+
+// RetryInterval - Acquire 等待锁时的重试间隔.
+func RetryInterval(interval time.Duration) Option {
+	return func(lk *Locker) {
+		lk.retryInterval = interval
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 	logrusadapter "logur.dev/adapter/logrus"
 	"logur.dev/logur"
 )
@@ -115,22 +116,25 @@ func (l *logger) WithField(key string, value interface{}) Logger {
 	return l.WithFields(map[string]interface{}{key: value})
 }
 
-// Ctx 从context中读取request_id.
+// Ctx 从context中读取request_id，以及 trace_id/span_id.
 func (l *logger) Ctx(c context.Context) Logger {
-	// 尝试从context中获取 *gin.Context
-	value := c.Value(gin.ContextKey)
-	if value == nil {
-		// 如果并不包含 gin.Context，那么就不做任何处理
-		return l
+	fields := map[string]interface{}{}
+
+	if sc := trace.SpanContextFromContext(c); sc.IsValid() {
+		fields["trace_id"] = sc.TraceID().String()
+		fields["span_id"] = sc.SpanID().String()
+	}
+
+	// 尝试从context中获取 *gin.Context，如果并不包含或者类型不对，那么就不记录 request_id
+	if d, ok := c.Value(gin.ContextKey).(*gin.Context); ok {
+		fields["request_id"] = requestid.Get(d)
 	}
 
-	d, ok := value.(*gin.Context)
-	if !ok {
-		// 如果不是 *gin.Context，那么就不做任何处理
+	if len(fields) == 0 {
 		return l
 	}
 
-	return l.WithField("request_id", requestid.Get(d))
+	return l.WithFields(fields)
 }
 
 // WithError = Err.
@@ -0,0 +1,173 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+	DialectPostgres = "postgres"
+)
+
+// 不同数据库在加锁、建表、标识符引用、占位符和时间函数上的差异.
+type dialect interface {
+	// 在 conn 上获取咨询锁，返回释放锁的函数
+	lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) (func() error, error)
+	// 创建记录迁移版本的表
+	createTable(table string) string
+	// 引用表名
+	quote(name string) string
+	// 将 ? 占位符转换为数据库的格式
+	rebind(query string) string
+	// 当前 UTC 时间的表达式
+	now() string
+}
+
+func newDialect(name string) (dialect, error) {
+	switch name {
+	case DialectMySQL:
+		return mysqlDialect{}, nil
+	case DialectSQLite:
+		return sqliteDialect{}, nil
+	case DialectPostgres:
+		return postgresDialect{}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownDialect, name)
+}
+
+type mysqlDialect struct{}
+
+// GET_LOCK 是连接级别的，因此需要在同一个连接上执行迁移.
+func (mysqlDialect) lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) (func() error, error) {
+	var locked sql.NullInt64
+
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, int(timeout.Seconds())).Scan(&locked)
+	if err != nil {
+		return nil, err
+	}
+
+	if !locked.Valid || locked.Int64 != 1 {
+		return nil, ErrLockTimeout
+	}
+
+	return func() error {
+		var released sql.NullInt64
+
+		return conn.QueryRowContext(context.Background(), "SELECT RELEASE_LOCK(?)", name).Scan(&released)
+	}, nil
+}
+
+func (mysqlDialect) createTable(table string) string {
+	return "CREATE TABLE IF NOT EXISTS `" + table + "` (" +
+		"`version` bigint unsigned PRIMARY KEY NOT NULL, " +
+		"`name` varchar(255) NOT NULL DEFAULT '', " +
+		"`dirty` tinyint(1) NOT NULL DEFAULT 0, " +
+		"`applied_at` datetime NOT NULL" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+}
+
+func (mysqlDialect) quote(name string) string {
+	return "`" + name + "`"
+}
+
+func (mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (mysqlDialect) now() string {
+	return "UTC_TIMESTAMP()"
+}
+
+type sqliteDialect struct{}
+
+// SQLite 只用于本地开发和测试，不存在多个副本并发迁移，不加锁.
+func (sqliteDialect) lock(context.Context, *sql.Conn, string, time.Duration) (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+func (sqliteDialect) createTable(table string) string {
+	return "CREATE TABLE IF NOT EXISTS `" + table + "` (" +
+		"`version` integer PRIMARY KEY NOT NULL, " +
+		"`name` varchar(255) NOT NULL DEFAULT '', " +
+		"`dirty` boolean NOT NULL DEFAULT 0, " +
+		"`applied_at` datetime NOT NULL" +
+		")"
+}
+
+func (sqliteDialect) quote(name string) string {
+	return "`" + name + "`"
+}
+
+func (sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (sqliteDialect) now() string {
+	return "CURRENT_TIMESTAMP"
+}
+
+type postgresDialect struct{}
+
+// pg_advisory_lock 同样是会话级别的，等待超时通过取消 ctx 实现.
+func (postgresDialect) lock(ctx context.Context, conn *sql.Conn, name string, timeout time.Duration) (func() error, error) {
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := conn.ExecContext(lockCtx, "SELECT pg_advisory_lock(hashtext($1))", name)
+	if errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+		return nil, ErrLockTimeout
+	} else if err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", name)
+
+		return err
+	}, nil
+}
+
+// dirty 使用 smallint 而不是 boolean，与其他数据库共用 dirty = 0 / 1 的语句.
+func (postgresDialect) createTable(table string) string {
+	return `CREATE TABLE IF NOT EXISTS "` + table + `" (` +
+		`"version" bigint PRIMARY KEY NOT NULL, ` +
+		`"name" varchar(255) NOT NULL DEFAULT '', ` +
+		`"dirty" smallint NOT NULL DEFAULT 0, ` +
+		`"applied_at" timestamptz NOT NULL` +
+		`)`
+}
+
+func (postgresDialect) quote(name string) string {
+	return `"` + name + `"`
+}
+
+// 迁移中使用的语句不包含字符串常量，可以直接按顺序替换 ?.
+func (postgresDialect) rebind(query string) string {
+	sb := strings.Builder{}
+	n := 0
+
+	for _, r := range query {
+		if r != '?' {
+			sb.WriteRune(r)
+
+			continue
+		}
+
+		n++
+		sb.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return sb.String()
+}
+
+func (postgresDialect) now() string {
+	return "CURRENT_TIMESTAMP"
+}
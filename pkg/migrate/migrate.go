@@ -0,0 +1,325 @@
+// Package migrate implements versioned schema migrations for MySQL, PostgreSQL and SQLite.
+//
+// 迁移文件通过 fs.FS 传入（一般使用 embed 打包进二进制），已执行的版本记录在 schema_migrations 表中.
+// 执行前获取咨询锁，避免多个副本同时启动时并发迁移：MySQL 使用 GET_LOCK，PostgreSQL 使用 pg_advisory_lock；
+// SQLite 只用于单实例部署，不加锁.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+const (
+	defaultTable       = "schema_migrations"
+	defaultLockName    = "schema_migrations"
+	defaultLockTimeout = time.Minute
+)
+
+var (
+	ErrDirty           = errors.New("database is dirty, fix the failed migration manually")
+	ErrLockTimeout     = errors.New("acquire migration lock timeout")
+	ErrVersionNotFound = errors.New("migration version not found")
+	ErrUnknownDialect  = errors.New("unknown migration dialect")
+)
+
+// Status 是某个版本的迁移状态.
+type Status struct {
+	Version   uint64     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	Dirty     bool       `json:"dirty"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+type applied struct {
+	name      string
+	dirty     bool
+	appliedAt time.Time
+}
+
+// Migrator -.
+type Migrator struct {
+	db          *sql.DB
+	l           logger.Logger
+	migrations  []Migration
+	table       string
+	lockName    string
+	lockTimeout time.Duration
+	dialectName string
+	dialect     dialect
+}
+
+// New -.
+func New(db *sql.DB, l logger.Logger, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:          db,
+		l:           l,
+		migrations:  migrations,
+		table:       defaultTable,
+		lockName:    defaultLockName,
+		lockTimeout: defaultLockTimeout,
+		dialectName: DialectMySQL,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.dialect, err = newDialect(m.dialectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Latest 返回最新的版本号，没有迁移文件时返回 0.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up 执行全部未执行的迁移.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down 回滚最近的一个迁移.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := done[m.migrations[i].Version]; ok {
+				return m.down(ctx, conn, m.migrations[i])
+			}
+		}
+
+		m.l.Info("migrate - Down - no migration to rollback")
+
+		return nil
+	})
+}
+
+// Goto 迁移到指定版本：执行所有 <= version 的未执行迁移，回滚所有 > version 的已执行迁移.
+// version 为 0 代表回滚全部.
+func (m *Migrator) Goto(ctx context.Context, version uint64) error {
+	if version != 0 && !m.exists(version) {
+		return fmt.Errorf("%w: %d", ErrVersionNotFound, version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for v := range done {
+			if v > version && !m.exists(v) {
+				return fmt.Errorf("%w: applied version %d has no migration file", ErrVersionNotFound, v)
+			}
+		}
+
+		// 先回滚高版本，再执行低版本
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mg := m.migrations[i]
+			if _, ok := done[mg.Version]; ok && mg.Version > version {
+				if err := m.down(ctx, conn, mg); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, mg := range m.migrations {
+			if _, ok := done[mg.Version]; !ok && mg.Version <= version {
+				if err := m.up(ctx, conn, mg); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status 返回全部迁移的状态.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var status []Status
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.queryApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mg := range m.migrations {
+			s := Status{Version: mg.Version, Name: mg.Name}
+			if a, ok := done[mg.Version]; ok {
+				appliedAt := a.appliedAt
+				s.Applied, s.Dirty, s.AppliedAt = true, a.dirty, &appliedAt
+			}
+
+			status = append(status, s)
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+func (m *Migrator) exists(version uint64) bool {
+	for _, mg := range m.migrations {
+		if mg.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 在同一个连接上持有咨询锁并执行 fn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate - withLock - get conn failed: %w", err)
+	}
+	defer conn.Close()
+
+	unlock, err := m.dialect.lock(ctx, conn, m.lockName, m.lockTimeout)
+	if errors.Is(err, ErrLockTimeout) {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("migrate - withLock - get lock failed: %w", err)
+	}
+
+	defer func() {
+		if err := unlock(); err != nil {
+			m.l.Warnf("migrate - withLock - release lock failed: %v", err)
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, m.dialect.createTable(m.table))
+	if err != nil {
+		return fmt.Errorf("migrate - withLock - create table %s failed: %w", m.table, err)
+	}
+
+	return fn(conn)
+}
+
+// 返回已执行的迁移，存在 dirty 版本时返回 ErrDirty.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[uint64]applied, error) {
+	done, err := m.queryApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	for v, a := range done {
+		if a.dirty {
+			return nil, fmt.Errorf("%w: version %d", ErrDirty, v)
+		}
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) queryApplied(ctx context.Context, conn *sql.Conn) (map[uint64]applied, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, dirty, applied_at FROM "+m.dialect.quote(m.table))
+	if err != nil {
+		return nil, fmt.Errorf("migrate - applied - query failed: %w", err)
+	}
+	defer rows.Close()
+
+	done := map[uint64]applied{}
+
+	for rows.Next() {
+		var (
+			v uint64
+			a applied
+		)
+
+		if err := rows.Scan(&v, &a.name, &a.dirty, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate - applied - scan failed: %w", err)
+		}
+
+		done[v] = a
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate - applied - rows failed: %w", err)
+	}
+
+	return done, nil
+}
+
+// MySQL 的 DDL 会隐式提交，无法放在事务中，因此执行前先标记 dirty，全部成功后再清除.
+func (m *Migrator) up(ctx context.Context, conn *sql.Conn, mg Migration) error {
+	m.l.Infof("migrate - up - applying %d_%s", mg.Version, mg.Name)
+
+	_, err := conn.ExecContext(ctx, m.dialect.rebind(
+		"INSERT INTO "+m.dialect.quote(m.table)+" (version, name, dirty, applied_at) VALUES (?, ?, 1, "+m.dialect.now()+")",
+	), mg.Version, mg.Name)
+	if err != nil {
+		return fmt.Errorf("migrate - up - mark version %d failed: %w", mg.Version, err)
+	}
+
+	if err := m.exec(ctx, conn, mg.Up); err != nil {
+		return fmt.Errorf("migrate - up - version %d failed: %w", mg.Version, err)
+	}
+
+	_, err = conn.ExecContext(ctx, m.dialect.rebind("UPDATE "+m.dialect.quote(m.table)+" SET dirty = 0 WHERE version = ?"), mg.Version)
+	if err != nil {
+		return fmt.Errorf("migrate - up - clear version %d failed: %w", mg.Version, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, mg Migration) error {
+	m.l.Infof("migrate - down - rolling back %d_%s", mg.Version, mg.Name)
+
+	_, err := conn.ExecContext(ctx, m.dialect.rebind("UPDATE "+m.dialect.quote(m.table)+" SET dirty = 1 WHERE version = ?"), mg.Version)
+	if err != nil {
+		return fmt.Errorf("migrate - down - mark version %d failed: %w", mg.Version, err)
+	}
+
+	if err := m.exec(ctx, conn, mg.Down); err != nil {
+		return fmt.Errorf("migrate - down - version %d failed: %w", mg.Version, err)
+	}
+
+	_, err = conn.ExecContext(ctx, m.dialect.rebind("DELETE FROM "+m.dialect.quote(m.table)+" WHERE version = ?"), mg.Version)
+	if err != nil {
+		return fmt.Errorf("migrate - down - delete version %d failed: %w", mg.Version, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) exec(ctx context.Context, conn *sql.Conn, body string) error {
+	for _, stmt := range splitStatements(body) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package migrate_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/migrate"
+	"github.com/ninehills/go-webapp-template/pkg/sqlite"
+	"github.com/ninehills/go-webapp-template/sql/sqlite/migrations"
+)
+
+func TestMigrateSQLite(t *testing.T) {
+	t.Parallel()
+
+	l := logger.New(logger.Config{Level: "error"})
+
+	db, err := sqlite.New(l, filepath.Join(t.TempDir(), "app.db"))
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	m, err := migrate.New(db.DB, l, migrations.FS, migrate.Dialect(migrate.DialectSQLite))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, m.Up(ctx))
+	// 重复执行不会报错
+	require.NoError(t, m.Up(ctx))
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status, int(m.Latest()))
+
+	for _, s := range status {
+		require.True(t, s.Applied)
+		require.False(t, s.Dirty)
+		require.NotNil(t, s.AppliedAt)
+	}
+
+	// 回滚全部迁移后可以重新执行
+	require.NoError(t, m.Goto(ctx, 0))
+
+	status, err = m.Status(ctx)
+	require.NoError(t, err)
+	require.False(t, status[0].Applied)
+
+	require.NoError(t, m.Up(ctx))
+
+	_, err = migrate.New(db.DB, l, migrations.FS, migrate.Dialect("oracle"))
+	require.ErrorIs(t, err, migrate.ErrUnknownDialect)
+}
@@ -0,0 +1,34 @@
+package migrate
+
+import "time"
+
+// Option -.
+type Option func(*Migrator)
+
+// Table - 记录迁移版本的表名.
+func Table(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// LockName - MySQL GET_LOCK / PostgreSQL pg_advisory_lock 使用的锁名称，同一个数据库的多个副本必须一致.
+func LockName(name string) Option {
+	return func(m *Migrator) {
+		m.lockName = name
+	}
+}
+
+// LockTimeout -.
+func LockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
+// Dialect - 数据库类型，DialectMySQL（默认）、DialectSQLite 或 DialectPostgres.
+func Dialect(name string) Option {
+	return func(m *Migrator) {
+		m.dialectName = name
+	}
+}
@@ -0,0 +1,52 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"000002_create_role.up.sql":   {Data: []byte("CREATE TABLE role (id int);")},
+		"000002_create_role.down.sql": {Data: []byte("DROP TABLE role;")},
+		"000001_create_user.up.sql":   {Data: []byte("CREATE TABLE user (id int);")},
+		"000010_noop.up.sql":          {Data: []byte("")},
+		"migrations.go":               {Data: []byte("package migrations")},
+	}
+
+	migrations, err := load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+	require.Equal(t, uint64(1), migrations[0].Version)
+	require.Equal(t, "create_user", migrations[0].Name)
+	require.Equal(t, "", migrations[0].Down)
+	require.Equal(t, uint64(2), migrations[1].Version)
+	require.Equal(t, "DROP TABLE role;", migrations[1].Down)
+	require.Equal(t, uint64(10), migrations[2].Version)
+
+	_, err = load(fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("")},
+		"000001_b.up.sql": {Data: []byte("")},
+	})
+	require.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	body := "-- comment; with semicolon\n" +
+		"CREATE TABLE `a` (`id` int COMMENT 'x;y') ENGINE=InnoDB;\n" +
+		"INSERT INTO a VALUES ('it\\'s; fine');  \n\n" +
+		"DROP TABLE b"
+
+	require.Equal(t, []string{
+		"CREATE TABLE `a` (`id` int COMMENT 'x;y') ENGINE=InnoDB",
+		"INSERT INTO a VALUES ('it\\'s; fine')",
+		"DROP TABLE b",
+	}, splitStatements(body))
+	require.Empty(t, splitStatements("-- only comment\n  ;\n"))
+}
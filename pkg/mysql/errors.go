@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	ErrCodeTooManyConnections = 1040
+	ErrCodeServerShutdown     = 1053
+	ErrCodeDuplicateEntry     = 1062
+	ErrCodeLockWaitTimeout    = 1205
+	ErrCodeDeadlock           = 1213
+	ErrCodeRowIsReferenced    = 1451
+	ErrCodeNoReferencedRow    = 1452
+)
+
+// Kind - 错误分类.
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	// 违反唯一索引
+	KindDuplicate
+	// 死锁，事务已被回滚
+	KindDeadlock
+	// 锁等待超时
+	KindLockWaitTimeout
+	// 删除或修改的行仍被外键引用
+	KindRowIsReferenced
+	// 外键引用的行不存在
+	KindNoReferencedRow
+	// 连接失败或断开
+	KindConnection
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindDuplicate:
+		return "duplicate"
+	case KindDeadlock:
+		return "deadlock"
+	case KindLockWaitTimeout:
+		return "lock-wait-timeout"
+	case KindRowIsReferenced:
+		return "row-is-referenced"
+	case KindNoReferencedRow:
+		return "no-referenced-row"
+	case KindConnection:
+		return "connection"
+	}
+
+	return "unknown"
+}
+
+//nolint:gochecknoglobals
+var (
+	// MySQL 8.0.19 之后索引名带有表名前缀：Duplicate entry 'a' for key 'user.email'
+	duplicateKeyRegexp = regexp.MustCompile(`for key '([^']+)'`)
+	// ... CONSTRAINT `fk_user_role_user` FOREIGN KEY ...
+	constraintRegexp = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+)
+
+// Error - 分类后的 MySQL 错误.
+type Error struct {
+	Kind Kind
+	// MySQL 错误码，连接错误时为 0
+	Number uint16
+	// 违反的唯一索引或外键约束名称（不含表名）
+	Index string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	if e.Index != "" {
+		return fmt.Sprintf("%s on %s: %v", e.Kind, e.Index, e.err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Kind, e.err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// NewError - 其他数据库（如 SQLite）的错误可以按相同的分类返回.
+func NewError(kind Kind, index string, err error) *Error {
+	return &Error{Kind: kind, Index: index, err: err}
+}
+
+// Classify - 对 MySQL 错误分类，err 为 nil 或者无法识别时返回 nil.
+func Classify(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		e := &Error{Number: me.Number, err: err}
+
+		switch me.Number {
+		case ErrCodeDuplicateEntry:
+			e.Kind = KindDuplicate
+			e.Index = matchName(duplicateKeyRegexp, me.Message)
+		case ErrCodeDeadlock:
+			e.Kind = KindDeadlock
+		case ErrCodeLockWaitTimeout:
+			e.Kind = KindLockWaitTimeout
+		case ErrCodeRowIsReferenced:
+			e.Kind = KindRowIsReferenced
+			e.Index = matchName(constraintRegexp, me.Message)
+		case ErrCodeNoReferencedRow:
+			e.Kind = KindNoReferencedRow
+			e.Index = matchName(constraintRegexp, me.Message)
+		case ErrCodeTooManyConnections, ErrCodeServerShutdown:
+			e.Kind = KindConnection
+		default:
+			return nil
+		}
+
+		return e
+	}
+
+	var ne net.Error
+	if errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) || errors.As(err, &ne) {
+		return &Error{Kind: KindConnection, err: err}
+	}
+
+	return nil
+}
+
+// IsDuplicate - 是否违反唯一索引.
+func IsDuplicate(err error) bool {
+	e := Classify(err)
+
+	return e != nil && e.Kind == KindDuplicate
+}
+
+// IsRetryable - 死锁和锁等待超时时事务已被回滚（或语句失败），可以重试整个事务.
+func IsRetryable(err error) bool {
+	e := Classify(err)
+
+	return e != nil && (e.Kind == KindDeadlock || e.Kind == KindLockWaitTimeout)
+}
+
+func matchName(re *regexp.Regexp, msg string) string {
+	match := re.FindStringSubmatch(msg)
+	if match == nil {
+		return ""
+	}
+
+	name := match[1]
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
@@ -0,0 +1,87 @@
+package mysql_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		err   error
+		kind  mysql.Kind
+		index string
+	}{
+		{
+			name:  "duplicate with table prefix",
+			err:   &gomysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@x.com' for key 'user.email'"},
+			kind:  mysql.KindDuplicate,
+			index: "email",
+		},
+		{
+			name:  "duplicate without table prefix",
+			err:   &gomysql.MySQLError{Number: 1062, Message: "Duplicate entry 'admin' for key 'username'"},
+			kind:  mysql.KindDuplicate,
+			index: "username",
+		},
+		{
+			name: "wrapped deadlock",
+			err: fmt.Errorf("update failed: %w",
+				&gomysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}),
+			kind: mysql.KindDeadlock,
+		},
+		{
+			name: "lock wait timeout",
+			err:  &gomysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"},
+			kind: mysql.KindLockWaitTimeout,
+		},
+		{
+			name: "row is referenced",
+			err: &gomysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row: " +
+				"a foreign key constraint fails (`app`.`user_role`, CONSTRAINT `fk_user_role_user` FOREIGN KEY (`user_id`))"},
+			kind:  mysql.KindRowIsReferenced,
+			index: "fk_user_role_user",
+		},
+		{
+			name: "no referenced row",
+			err: &gomysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: " +
+				"a foreign key constraint fails (`app`.`user_role`, CONSTRAINT `fk_user_role_role` FOREIGN KEY (`role_id`))"},
+			kind:  mysql.KindNoReferencedRow,
+			index: "fk_user_role_role",
+		},
+		{name: "bad conn", err: driver.ErrBadConn, kind: mysql.KindConnection},
+		{name: "invalid conn", err: gomysql.ErrInvalidConn, kind: mysql.KindConnection},
+		{name: "too many connections", err: &gomysql.MySQLError{Number: 1040}, kind: mysql.KindConnection},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := mysql.Classify(tc.err)
+			require.NotNil(t, e)
+			require.Equal(t, tc.kind, e.Kind)
+			require.Equal(t, tc.index, e.Index)
+			require.ErrorIs(t, e, tc.err)
+		})
+	}
+
+	require.Nil(t, mysql.Classify(nil))
+	require.Nil(t, mysql.Classify(errors.New("other")))
+	require.Nil(t, mysql.Classify(&gomysql.MySQLError{Number: 1064, Message: "syntax error"}))
+
+	require.True(t, mysql.IsDuplicate(&gomysql.MySQLError{Number: 1062}))
+	require.True(t, mysql.IsRetryable(&gomysql.MySQLError{Number: 1213}))
+	require.False(t, mysql.IsRetryable(driver.ErrBadConn))
+}
@@ -13,20 +13,32 @@ type ctxKeySQLStarted struct{}
 type Hook struct {
 	// FIXME: this logger cannot auto-reload.
 	l logger.Logger
+	// OpenTelemetry 的 db.system，同时作为 span 名称的前缀
+	system string
 }
 
 func NewHook(l logger.Logger) *Hook {
+	return NewSystemHook(l, "mysql")
+}
+
+// NewSystemHook - 其他基于 database/sql 的数据库（如 PostgreSQL）复用相同的日志、指标和 Tracing.
+func NewSystemHook(l logger.Logger, system string) *Hook {
 	return &Hook{
-		l: l,
+		l:      l,
+		system: system,
 	}
 }
 
-func (h *Hook) Before(ctx context.Context, _ string, _ ...interface{}) (context.Context, error) {
+func (h *Hook) Before(ctx context.Context, query string, _ ...interface{}) (context.Context, error) {
+	ctx = startSpan(ctx, h.system, query)
+
 	return context.WithValue(ctx, ctxKeySQLStarted{}, time.Now()), nil
 }
 
 func (h *Hook) After(ctx context.Context, query string, args ...interface{}) (context.Context, error) {
-	h.l.Debugf("SQL Query: `%s`, Args: `%q`. took: %s",
+	observeDuration(ctx, query)
+	endSpan(ctx, nil)
+	h.l.Ctx(ctx).Debugf("SQL Query: `%s`, Args: `%q`. took: %s",
 		strings.ReplaceAll(query, "\n", " "), args, getTimeUsedFromCtx(ctx),
 	)
 
@@ -34,13 +46,25 @@ func (h *Hook) After(ctx context.Context, query string, args ...interface{}) (co
 }
 
 func (h *Hook) OnError(ctx context.Context, err error, query string, args ...interface{}) error {
-	h.l.Debugf("SQL Error: %v, Query: `%s`, Args: `%q`, Took: %s",
+	observeDuration(ctx, query)
+	queryErrors.WithLabelValues(queryName(query)).Inc()
+	endSpan(ctx, err)
+	h.l.Ctx(ctx).Debugf("SQL Error: %v, Query: `%s`, Args: `%q`, Took: %s",
 		err, strings.ReplaceAll(query, "\n", " "), args, getTimeUsedFromCtx(ctx),
 	)
 
 	return err
 }
 
+func observeDuration(ctx context.Context, query string) {
+	started, ok := ctx.Value(ctxKeySQLStarted{}).(time.Time)
+	if !ok {
+		return
+	}
+
+	queryDuration.WithLabelValues(queryName(query)).Observe(time.Since(started).Seconds())
+}
+
 func getTimeUsedFromCtx(ctx context.Context) string {
 	started, ok := ctx.Value(ctxKeySQLStarted{}).(time.Time)
 	if !ok {
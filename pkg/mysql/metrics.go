@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 没有 sqlc `-- name:` 注释的语句使用的 query label.
+const unnamedQuery = "unnamed"
+
+//nolint:gochecknoglobals
+var (
+	queryNameRegexp = regexp.MustCompile(`^\s*-- name: (\w+)`)
+
+	queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "SQL query latency by sqlc query name.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query"})
+
+	queryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Total number of failed SQL queries by sqlc query name.",
+	}, []string{"query"})
+)
+
+// 从 sqlc 生成的语句中解析查询名称，如 "-- name: GetUser :one" 解析为 GetUser.
+func queryName(query string) string {
+	match := queryNameRegexp.FindStringSubmatch(query)
+	if match == nil {
+		return unnamedQuery
+	}
+
+	return match[1]
+}
+
+// 注册连接池指标 go_sql_*，重复注册时忽略.
+func registerDBStats(db *sql.DB, dbName string) error {
+	err := prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+
+	var are prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &are) {
+		return err
+	}
+
+	return nil
+}
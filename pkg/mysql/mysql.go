@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -17,23 +18,33 @@ const (
 	defaultConnMaxLifetime = time.Second * 120
 	connAttempts           = 3
 	connAttemptPeriod      = time.Second * 5
+
+	defaultReplicaCheckInterval = time.Second * 5
 )
 
 // MySQL -.
 type MySQL struct {
-	maxOpenConns    int
-	maxIdleConns    int
-	connMaxLifetime time.Duration
-
+	maxOpenConns         int
+	maxIdleConns         int
+	connMaxLifetime      time.Duration
+	replicaDSNs          []string
+	replicaCheckInterval time.Duration
+	readPrimary          func(ctx context.Context) bool
+
+	// 主库，用于写操作、事务、迁移和健康检查
 	DB *sql.DB
+	// 读写分离的 DBTX，没有配置副本时全部使用主库
+	Router   *Router
+	replicas []*sql.DB
 }
 
 // New -.
 func New(l logger.Logger, dsn string, opts ...Option) (*MySQL, error) {
 	ms := &MySQL{
-		maxOpenConns:    defaultMaxOpenConns,
-		maxIdleConns:    defaultMaxIdleConns,
-		connMaxLifetime: defaultConnMaxLifetime,
+		maxOpenConns:         defaultMaxOpenConns,
+		maxIdleConns:         defaultMaxIdleConns,
+		connMaxLifetime:      defaultConnMaxLifetime,
+		replicaCheckInterval: defaultReplicaCheckInterval,
 	}
 
 	// Custom options
@@ -52,9 +63,12 @@ func New(l logger.Logger, dsn string, opts ...Option) (*MySQL, error) {
 		return nil, fmt.Errorf("mysql - NewMySQL - Open mysql database failed: %w", err)
 	}
 
-	ms.DB.SetConnMaxLifetime(ms.connMaxLifetime)
-	ms.DB.SetMaxOpenConns(ms.maxOpenConns)
-	ms.DB.SetMaxIdleConns(ms.maxIdleConns)
+	ms.setPool(ms.DB)
+
+	err = registerDBStats(ms.DB, "mysql")
+	if err != nil {
+		return nil, fmt.Errorf("mysql - NewMySQL - register db stats metrics failed: %w", err)
+	}
 
 	i := connAttempts
 	for i > 0 {
@@ -73,11 +87,49 @@ func New(l logger.Logger, dsn string, opts ...Option) (*MySQL, error) {
 		return nil, fmt.Errorf("mysql - NewMySQL - Connect attempts %d times failed: %w", connAttempts, err)
 	}
 
+	// 副本不可用时不影响启动，由 Router 的健康检查摘除
+	for i, dsn := range ms.replicaDSNs {
+		db, err := sql.Open("mysqllog", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("mysql - NewMySQL - Open replica %d failed: %w", i, err)
+		}
+
+		ms.setPool(db)
+
+		err = registerDBStats(db, fmt.Sprintf("mysql-replica-%d", i))
+		if err != nil {
+			return nil, fmt.Errorf("mysql - NewMySQL - register replica %d db stats metrics failed: %w", i, err)
+		}
+
+		ms.replicas = append(ms.replicas, db)
+	}
+
+	ms.Router = NewRouter(l, ms.DB, ms.replicas, ms.replicaCheckInterval, ms.readPrimary)
+
 	return ms, nil
 }
 
+func (m *MySQL) setPool(db *sql.DB) {
+	db.SetConnMaxLifetime(m.connMaxLifetime)
+	db.SetMaxOpenConns(m.maxOpenConns)
+	db.SetMaxIdleConns(m.maxIdleConns)
+}
+
+// Ping - 用于健康检查.
+func (m *MySQL) Ping(ctx context.Context) error {
+	return m.DB.PingContext(ctx)
+}
+
 // Close -.
 func (m *MySQL) Close() {
+	if m.Router != nil {
+		m.Router.Close()
+	}
+
+	for _, db := range m.replicas {
+		db.Close()
+	}
+
 	if m.DB != nil {
 		m.DB.Close()
 	}
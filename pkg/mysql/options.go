@@ -1,6 +1,9 @@
 package mysql
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Option -.
 type Option func(*MySQL)
@@ -25,3 +28,24 @@ func MaxIdleConns(conns int) Option {
 		c.maxIdleConns = conns
 	}
 }
+
+// Replicas - 只读副本的 DSN，查询通过 MySQL.Router 分发到副本.
+func Replicas(dsns ...string) Option {
+	return func(c *MySQL) {
+		c.replicaDSNs = dsns
+	}
+}
+
+// ReplicaCheckInterval - 副本健康检查间隔.
+func ReplicaCheckInterval(seconds int) Option {
+	return func(c *MySQL) {
+		c.replicaCheckInterval = time.Second * time.Duration(seconds)
+	}
+}
+
+// ReadPrimary - 判断查询是否需要读取主库，如写入之后立即读取；默认所有查询都可以读取副本.
+func ReadPrimary(fn func(ctx context.Context) bool) Option {
+	return func(c *MySQL) {
+		c.readPrimary = fn
+	}
+}
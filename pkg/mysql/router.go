@@ -0,0 +1,161 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+type replica struct {
+	// 副本序号，日志中不输出 DSN
+	index   int
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// Router 实现 sqlc 的 DBTX：ExecContext / PrepareContext 使用主库，QueryContext / QueryRowContext 轮询健康的副本.
+// 事务由主库的 *sql.DB 开启，不经过 Router，因此事务中的读写都在主库执行.
+// 副本由后台健康检查摘除和恢复，查询遇到连接错误时立即摘除该副本并改用主库重试.
+type Router struct {
+	primary     *sql.DB
+	replicas    []*replica
+	next        atomic.Uint64
+	readPrimary func(ctx context.Context) bool
+	l           logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRouter - 没有副本时所有操作都使用主库，interval 为副本健康检查的间隔；
+// readPrimary 判断查询是否需要读取主库（如写入之后立即读取），为 nil 时所有查询都可以读取副本.
+func NewRouter(
+	l logger.Logger, primary *sql.DB, replicas []*sql.DB, interval time.Duration, readPrimary func(ctx context.Context) bool,
+) *Router {
+	r := &Router{
+		primary:     primary,
+		readPrimary: readPrimary,
+		l:           l,
+		done:        make(chan struct{}),
+	}
+
+	for i, db := range replicas {
+		rep := &replica{index: i, db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+
+	if len(r.replicas) > 0 && interval > 0 {
+		r.check(interval)
+		go r.run(interval)
+	}
+
+	return r
+}
+
+func (r *Router) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *Router) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+func (r *Router) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rep := r.pick(ctx)
+	if rep == nil {
+		return r.primary.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := rep.db.QueryContext(ctx, query, args...)
+	if err != nil && r.eject(ctx, rep, err) {
+		return r.primary.QueryContext(ctx, query, args...)
+	}
+
+	return rows, err
+}
+
+func (r *Router) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	rep := r.pick(ctx)
+	if rep == nil {
+		return r.primary.QueryRowContext(ctx, query, args...)
+	}
+
+	row := rep.db.QueryRowContext(ctx, query, args...)
+	if err := row.Err(); err != nil && r.eject(ctx, rep, err) {
+		return r.primary.QueryRowContext(ctx, query, args...)
+	}
+
+	return row
+}
+
+// Close - 停止健康检查，不关闭连接.
+func (r *Router) Close() {
+	r.closeOnce.Do(func() { close(r.done) })
+}
+
+// 轮询选择健康的副本，要求使用主库或者没有健康的副本时返回 nil.
+func (r *Router) pick(ctx context.Context) *replica {
+	n := uint64(len(r.replicas))
+	if n == 0 || (r.readPrimary != nil && r.readPrimary(ctx)) {
+		return nil
+	}
+
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+// 连接错误时摘除副本，返回是否需要改用主库重试；ctx 取消或超时不代表副本不可用.
+func (r *Router) eject(ctx context.Context, rep *replica, err error) bool {
+	e := Classify(err)
+	if ctx.Err() != nil || e == nil || e.Kind != KindConnection {
+		return false
+	}
+
+	if rep.healthy.CompareAndSwap(true, false) {
+		r.l.Ctx(ctx).Warnf("mysql - Router - replica %d ejected: %v", rep.index, err)
+	}
+
+	return true
+}
+
+func (r *Router) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.check(interval)
+		}
+	}
+}
+
+func (r *Router) check(timeout time.Duration) {
+	for _, rep := range r.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := rep.db.PingContext(ctx)
+
+		cancel()
+
+		switch {
+		case err != nil && rep.healthy.CompareAndSwap(true, false):
+			r.l.Warnf("mysql - Router - replica %d ejected: %v", rep.index, err)
+		case err == nil && rep.healthy.CompareAndSwap(false, true):
+			r.l.Infof("mysql - Router - replica %d recovered", rep.index)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+)
+
+type ctxKeyPrimary struct{}
+
+func readPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(ctxKeyPrimary{}).(bool)
+
+	return primary
+}
+
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db, mock
+}
+
+func scanInt(t *testing.T, row *sql.Row) int {
+	t.Helper()
+
+	var n int
+	require.NoError(t, row.Scan(&n))
+
+	return n
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	l := logger.New(logger.Config{Format: "text", Level: "error"})
+	primary, pm := newMockDB(t)
+	replica0, rm0 := newMockDB(t)
+	replica1, rm1 := newMockDB(t)
+
+	r := mysql.NewRouter(l, primary, []*sql.DB{replica0, replica1}, 0, readPrimary)
+	defer r.Close()
+
+	ctx := context.Background()
+	row := func(n int) *sqlmock.Rows { return sqlmock.NewRows([]string{"n"}).AddRow(n) }
+
+	// 写操作使用主库
+	pm.ExpectExec("UPDATE user").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := r.ExecContext(ctx, "UPDATE user SET status = 1")
+	require.NoError(t, err)
+
+	// 读操作轮询副本
+	rm1.ExpectQuery("SELECT n").WillReturnRows(row(1))
+	rm0.ExpectQuery("SELECT n").WillReturnRows(row(0))
+	rm1.ExpectQuery("SELECT n").WillReturnRows(row(1))
+
+	require.Equal(t, 1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+	require.Equal(t, 0, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+
+	rows, err := r.QueryContext(ctx, "SELECT n")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	// 要求读取主库
+	pm.ExpectQuery("SELECT n").WillReturnRows(row(-1))
+	require.Equal(t, -1, scanInt(t, r.QueryRowContext(context.WithValue(ctx, ctxKeyPrimary{}, true), "SELECT n")))
+
+	// 连接错误时摘除副本并改用主库，之后的查询只发送到健康的副本
+	rm0.ExpectQuery("SELECT n").WillReturnError(gomysql.ErrInvalidConn)
+	pm.ExpectQuery("SELECT n").WillReturnRows(row(-1))
+	require.Equal(t, -1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+
+	rm1.ExpectQuery("SELECT n").WillReturnRows(row(1))
+	rm1.ExpectQuery("SELECT n").WillReturnRows(row(1))
+	require.Equal(t, 1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+	require.Equal(t, 1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+
+	// 其他错误直接返回，不摘除副本
+	rm1.ExpectQuery("SELECT n").WillReturnError(sql.ErrConnDone)
+
+	_, err = r.QueryContext(ctx, "SELECT n")
+	require.ErrorIs(t, err, sql.ErrConnDone)
+
+	// 没有健康的副本时使用主库
+	rm1.ExpectQuery("SELECT n").WillReturnError(gomysql.ErrInvalidConn)
+	pm.ExpectQuery("SELECT n").WillReturnRows(row(-1))
+	pm.ExpectQuery("SELECT n").WillReturnRows(row(-1))
+	require.Equal(t, -1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+	require.Equal(t, -1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+
+	for _, m := range []sqlmock.Sqlmock{pm, rm0, rm1} {
+		require.NoError(t, m.ExpectationsWereMet())
+	}
+}
+
+func TestRouterRecover(t *testing.T) {
+	t.Parallel()
+
+	l := logger.New(logger.Config{Format: "text", Level: "error"})
+	primary, pm := newMockDB(t)
+	replica, rm := newMockDB(t)
+
+	r := mysql.NewRouter(l, primary, []*sql.DB{replica}, 10*time.Millisecond, readPrimary)
+	defer r.Close()
+
+	ctx := context.Background()
+
+	rm.ExpectQuery("SELECT n").WillReturnError(gomysql.ErrInvalidConn)
+	pm.ExpectQuery("SELECT n").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(-1))
+	require.Equal(t, -1, scanInt(t, r.QueryRowContext(ctx, "SELECT n")))
+
+	// 健康检查成功后副本重新接收查询
+	rm.ExpectQuery("SELECT n").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(0))
+	require.Eventually(t, func() bool {
+		var n int
+		if err := r.QueryRowContext(ctx, "SELECT n").Scan(&n); err != nil {
+			return false
+		}
+
+		return n == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ninehills/go-webapp-template/pkg/mysql"
+
+// 为每条 SQL 创建子 span，span 名称使用 sqlc 的查询名称.
+func startSpan(ctx context.Context, system, query string) context.Context {
+	name := queryName(query)
+
+	ctx, _ = otel.Tracer(tracerName).Start(ctx, system+" "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(system),
+			semconv.DBOperation(name),
+			semconv.DBStatement(query),
+		),
+	)
+
+	return ctx
+}
+
+func endSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -23,8 +23,11 @@ func EncryptPassword(password string) (string, error) {
 // 检查密码的Hash和密码是否匹配.
 func CompareHashAndPassword(hash, password string) error {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return fmt.Errorf("bcrypt.CompareHashAndPassword failed: %w", err)
+	}
 
-	return fmt.Errorf("bcrypt.CompareHashAndPassword failed: %w", err)
+	return nil
 }
 
 // 检查密码是否符合格式要求.
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	ErrCodeUniqueViolation      = "23505"
+	ErrCodeForeignKeyViolation  = "23503"
+	ErrCodeSerializationFailure = "40001"
+	ErrCodeDeadlockDetected     = "40P01"
+	ErrCodeLockNotAvailable     = "55P03"
+	ErrCodeTooManyConnections   = "53300"
+	ErrCodeAdminShutdown        = "57P01"
+	ErrCodeCannotConnectNow     = "57P03"
+	// 08xxx: connection_exception
+	errClassConnection = "08"
+)
+
+// Classify - 将 PostgreSQL 错误按 MySQL 错误的分类返回，上层使用相同的方式处理，无法识别时返回 nil.
+// 唯一约束使用默认名称 <table>_<column>_key 时 Index 为字段名，否则为约束名称.
+// 可序列化事务的冲突（40001）与死锁一样需要重试整个事务，归类为 KindDeadlock.
+func Classify(err error) *mysql.Error {
+	if err == nil {
+		return nil
+	}
+
+	var pe *pgconn.PgError
+	if errors.As(err, &pe) {
+		switch {
+		case pe.Code == ErrCodeUniqueViolation:
+			return mysql.NewError(mysql.KindDuplicate, uniqueColumn(pe.TableName, pe.ConstraintName), err)
+		case pe.Code == ErrCodeForeignKeyViolation && strings.Contains(pe.Detail, "is still referenced"):
+			return mysql.NewError(mysql.KindRowIsReferenced, pe.ConstraintName, err)
+		case pe.Code == ErrCodeForeignKeyViolation:
+			return mysql.NewError(mysql.KindNoReferencedRow, pe.ConstraintName, err)
+		case pe.Code == ErrCodeDeadlockDetected, pe.Code == ErrCodeSerializationFailure:
+			return mysql.NewError(mysql.KindDeadlock, "", err)
+		case pe.Code == ErrCodeLockNotAvailable:
+			return mysql.NewError(mysql.KindLockWaitTimeout, "", err)
+		case pe.Code == ErrCodeTooManyConnections, pe.Code == ErrCodeAdminShutdown, pe.Code == ErrCodeCannotConnectNow,
+			strings.HasPrefix(pe.Code, errClassConnection):
+			return mysql.NewError(mysql.KindConnection, "", err)
+		}
+
+		return nil
+	}
+
+	// 连接失败时 pgx 返回的错误会包装底层的 net.Error
+	var ne net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &ne) {
+		return mysql.NewError(mysql.KindConnection, "", err)
+	}
+
+	return nil
+}
+
+// IsDuplicate - 是否违反唯一约束.
+func IsDuplicate(err error) bool {
+	e := Classify(err)
+
+	return e != nil && e.Kind == mysql.KindDuplicate
+}
+
+// IsRetryable - 死锁、序列化冲突和锁等待超时时可以重试整个事务.
+func IsRetryable(err error) bool {
+	e := Classify(err)
+
+	return e != nil && (e.Kind == mysql.KindDeadlock || e.Kind == mysql.KindLockWaitTimeout)
+}
+
+// user_email_key -> email.
+func uniqueColumn(table, constraint string) string {
+	if table == "" || !strings.HasPrefix(constraint, table+"_") || !strings.HasSuffix(constraint, "_key") {
+		return constraint
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(constraint, table+"_"), "_key")
+}
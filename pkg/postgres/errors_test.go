@@ -0,0 +1,92 @@
+package postgres_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+	"github.com/ninehills/go-webapp-template/pkg/postgres"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		err   error
+		kind  mysql.Kind
+		index string
+	}{
+		{
+			name:  "duplicate with default constraint name",
+			err:   &pgconn.PgError{Code: "23505", TableName: "user", ConstraintName: "user_email_key"},
+			kind:  mysql.KindDuplicate,
+			index: "email",
+		},
+		{
+			name:  "duplicate with custom constraint name",
+			err:   &pgconn.PgError{Code: "23505", TableName: "user_role", ConstraintName: "uk_user_role"},
+			kind:  mysql.KindDuplicate,
+			index: "uk_user_role",
+		},
+		{
+			name: "wrapped deadlock",
+			err:  fmt.Errorf("update failed: %w", &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}),
+			kind: mysql.KindDeadlock,
+		},
+		{
+			name: "serialization failure",
+			err:  &pgconn.PgError{Code: "40001"},
+			kind: mysql.KindDeadlock,
+		},
+		{
+			name: "lock not available",
+			err:  &pgconn.PgError{Code: "55P03"},
+			kind: mysql.KindLockWaitTimeout,
+		},
+		{
+			name: "row is referenced",
+			err: &pgconn.PgError{Code: "23503", ConstraintName: "fk_user_role_user",
+				Detail: `Key (id)=(1) is still referenced from table "user_role".`},
+			kind:  mysql.KindRowIsReferenced,
+			index: "fk_user_role_user",
+		},
+		{
+			name: "no referenced row",
+			err: &pgconn.PgError{Code: "23503", ConstraintName: "fk_user_role_role",
+				Detail: `Key (role_id)=(9) is not present in table "role".`},
+			kind:  mysql.KindNoReferencedRow,
+			index: "fk_user_role_role",
+		},
+		{name: "bad conn", err: driver.ErrBadConn, kind: mysql.KindConnection},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, kind: mysql.KindConnection},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, kind: mysql.KindConnection},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := postgres.Classify(tc.err)
+			require.NotNil(t, e)
+			require.Equal(t, tc.kind, e.Kind)
+			require.Equal(t, tc.index, e.Index)
+			require.ErrorIs(t, e, tc.err)
+		})
+	}
+
+	require.Nil(t, postgres.Classify(nil))
+	require.Nil(t, postgres.Classify(errors.New("other")))
+	require.Nil(t, postgres.Classify(&pgconn.PgError{Code: "42601", Message: "syntax error"}))
+
+	require.True(t, postgres.IsDuplicate(&pgconn.PgError{Code: "23505"}))
+	require.True(t, postgres.IsRetryable(&pgconn.PgError{Code: "40P01"}))
+	require.False(t, postgres.IsRetryable(driver.ErrBadConn))
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import "time"
+
+// Option -.
+type Option func(*Postgres)
+
+// ConnMaxLifetime -.
+func ConnMaxLifetime(seconds int) Option {
+	return func(c *Postgres) {
+		c.connMaxLifetime = time.Second * time.Duration(seconds)
+	}
+}
+
+// MaxOpenConns -.
+func MaxOpenConns(conns int) Option {
+	return func(c *Postgres) {
+		c.maxOpenConns = conns
+	}
+}
+
+// MaxIdleConns -.
+func MaxIdleConns(conns int) Option {
+	return func(c *Postgres) {
+		c.maxIdleConns = conns
+	}
+}
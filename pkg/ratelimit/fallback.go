@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+//nolint:gochecknoglobals
+var fallbacks = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "ratelimit_fallbacks_total",
+	Help: "Total number of rate limit checks served by the fallback limiter because the primary failed.",
+})
+
+// Fallback 在 primary（通常是 Redis）出错时降级到 secondary（通常是 Memory），
+// 降级期间配额按实例计算，但不会因为 Redis 故障放开全部流量.
+type Fallback struct {
+	primary   Limiter
+	secondary Limiter
+	l         logger.Logger
+}
+
+// NewFallback -.
+func NewFallback(primary, secondary Limiter, l logger.Logger) *Fallback {
+	return &Fallback{
+		primary:   primary,
+		secondary: secondary,
+		l:         l,
+	}
+}
+
+// Allow -.
+func (f *Fallback) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := f.primary.Allow(ctx, key, limit)
+	if err == nil || errors.Is(err, ErrInvalidLimit) {
+		return res, err
+	}
+
+	fallbacks.Inc()
+	f.l.Ctx(ctx).Err(err).Warn("ratelimit - Fallback - primary limiter failed, use secondary")
+
+	return f.secondary.Allow(ctx, key, limit)
+}
@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// 清理过期计数的间隔，避免 key 无限增长.
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	// 滑动窗口的请求时间
+	hits []time.Time
+	// 令牌桶状态
+	tokens float64
+	ts     time.Time
+
+	expireAt time.Time
+}
+
+// Memory 进程内的限流器，配额不在实例之间共享，用于开发环境或 Redis 不可用时降级.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemory -.
+func NewMemory() *Memory {
+	return &Memory{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Allow -.
+func (m *Memory) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if limit.Algorithm == AlgorithmTokenBucket {
+		return m.tokenBucket(now, "tb:"+key, limit), nil
+	}
+
+	return m.slidingWindow(now, "sw:"+key, limit), nil
+}
+
+func (m *Memory) slidingWindow(now time.Time, key string, limit Limit) Result {
+	e := m.entries[key]
+	if e == nil {
+		e = &memoryEntry{}
+		m.entries[key] = e
+	}
+
+	// 丢弃窗口之外的请求
+	start := now.Add(-limit.Period)
+	i := 0
+
+	for i < len(e.hits) && !e.hits[i].After(start) {
+		i++
+	}
+
+	e.hits = e.hits[i:]
+
+	res := Result{Limit: limit.Rate}
+	if len(e.hits) < limit.Rate {
+		e.hits = append(e.hits, now)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = e.hits[0].Add(limit.Period).Sub(now)
+	}
+
+	res.Remaining = limit.Rate - len(e.hits)
+	e.expireAt = e.hits[len(e.hits)-1].Add(limit.Period)
+	res.ResetAfter = e.expireAt.Sub(now)
+
+	return res
+}
+
+func (m *Memory) tokenBucket(now time.Time, key string, limit Limit) Result {
+	capacity := float64(limit.capacity())
+	// 每纳秒补充的令牌数
+	rate := float64(limit.Rate) / float64(limit.Period)
+
+	e := m.entries[key]
+	if e == nil {
+		e = &memoryEntry{tokens: capacity, ts: now}
+		m.entries[key] = e
+	}
+
+	if elapsed := now.Sub(e.ts); elapsed > 0 {
+		e.tokens = math.Min(capacity, e.tokens+float64(elapsed)*rate)
+	}
+
+	e.ts = now
+
+	res := Result{Limit: limit.capacity()}
+	if e.tokens >= 1 {
+		e.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - e.tokens) / rate))
+	}
+
+	res.Remaining = int(e.tokens)
+	res.ResetAfter = time.Duration(math.Ceil((capacity - e.tokens) / rate))
+	e.expireAt = now.Add(res.ResetAfter)
+
+	return res
+}
+
+func (m *Memory) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+
+	for key, e := range m.entries {
+		if !now.Before(e.expireAt) {
+			delete(m.entries, key)
+		}
+	}
+
+	m.nextSweep = now.Add(memorySweepInterval)
+}
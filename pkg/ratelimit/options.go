@@ -0,0 +1,20 @@
+package ratelimit
+
+import "time"
+
+// Option -.
+type Option func(*Redis)
+
+// Prefix - 限流计数在 Redis 中的 key 前缀，默认为 "ratelimit:".
+func Prefix(prefix string) Option {
+	return func(r *Redis) {
+		r.prefix = prefix
+	}
+}
+
+// Clock - 当前时间，默认为 time.Now，多实例之间的时钟偏差会影响精度.
+func Clock(now func() time.Time) Option {
+	return func(r *Redis) {
+		r.now = now
+	}
+}
@@ -0,0 +1,108 @@
+// Package ratelimit implements sliding window and token bucket rate limiters on Redis, with an in-memory fallback.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// 滑动窗口（日志），Period 内最多 Rate 次请求，精确但每个请求占用一个 ZSET 成员.
+	AlgorithmSlidingWindow = "sliding-window"
+	// 令牌桶，按 Rate/Period 匀速补充令牌，容量为 Burst，允许短时突发.
+	AlgorithmTokenBucket = "token-bucket"
+)
+
+var (
+	ErrInvalidLimit = errors.New("invalid rate limit")
+	ErrStorage      = errors.New("ratelimit storage visit error")
+)
+
+// Limit 描述一条限流规则.
+type Limit struct {
+	// sliding-window(默认), token-bucket
+	Algorithm string
+	// 每个 Period 内允许的请求数
+	Rate   int
+	Period time.Duration
+	// 令牌桶容量，<= 0 时等于 Rate，滑动窗口忽略
+	Burst int
+}
+
+func (l Limit) validate() error {
+	if l.Rate <= 0 || l.Period <= 0 {
+		return fmt.Errorf("%w: rate and period must be positive", ErrInvalidLimit)
+	}
+
+	switch l.Algorithm {
+	case "", AlgorithmSlidingWindow, AlgorithmTokenBucket:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidLimit, l.Algorithm)
+	}
+}
+
+func (l Limit) capacity() int {
+	if l.Algorithm == AlgorithmTokenBucket && l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Rate
+}
+
+// Result 是一次 Allow 的结果，用于填充 X-RateLimit-* 响应头.
+type Result struct {
+	Allowed bool
+	// 配额上限，令牌桶为桶容量
+	Limit     int
+	Remaining int
+	// 被拒绝时至少需要等待的时间，允许时为 0
+	RetryAfter time.Duration
+	// 配额完全恢复需要的时间
+	ResetAfter time.Duration
+}
+
+// Limiter -.
+type Limiter interface {
+	// 消耗 key 的一次配额.
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rule 是按名称引用的限流规则，Key 为限流维度（如 ip、user、route），由调用方解释.
+type Rule struct {
+	Limit
+	Key string
+}
+
+// Rules 保存按名称声明的规则，可以在运行时整体替换，用于配置热加载.
+type Rules struct {
+	v atomic.Value
+}
+
+// NewRules -.
+func NewRules(rules map[string]Rule) *Rules {
+	r := &Rules{}
+	r.Store(rules)
+
+	return r
+}
+
+// Store 整体替换规则，调用方之后不应再修改 rules.
+func (r *Rules) Store(rules map[string]Rule) {
+	if rules == nil {
+		rules = map[string]Rule{}
+	}
+
+	r.v.Store(rules)
+}
+
+// Get -.
+func (r *Rules) Get(name string) (Rule, bool) {
+	rules, _ := r.v.Load().(map[string]Rule)
+	rule, ok := rules[name]
+
+	return rule, ok
+}
@@ -0,0 +1,206 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+	"github.com/ninehills/go-webapp-template/pkg/ratelimit"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newRedis(t *testing.T) (*ratelimit.Redis, *fakeClock, *miniredis.Miniredis) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	return ratelimit.NewRedis(rdb, ratelimit.Clock(clock.Now)), clock, m
+}
+
+func TestRedisSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	rl, clock, _ := newRedis(t)
+	ctx := context.Background()
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmSlidingWindow, Rate: 3, Period: time.Minute}
+
+	for i := 0; i < 3; i++ {
+		res, err := rl.Allow(ctx, "login:ip:1.2.3.4", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 3, res.Limit)
+		require.Equal(t, 2-i, res.Remaining)
+
+		clock.Advance(10 * time.Second)
+	}
+
+	// 窗口内第 4 次被拒绝，需要等到第一次请求滑出窗口
+	res, err := rl.Allow(ctx, "login:ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, 30*time.Second, res.RetryAfter)
+	require.Equal(t, 50*time.Second, res.ResetAfter)
+
+	// 其他 key 不受影响
+	res, err = rl.Allow(ctx, "login:ip:5.6.7.8", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	clock.Advance(30 * time.Second)
+
+	res, err = rl.Allow(ctx, "login:ip:1.2.3.4", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+}
+
+func TestRedisTokenBucket(t *testing.T) {
+	t.Parallel()
+
+	rl, clock, _ := newRedis(t)
+	ctx := context.Background()
+	// 每秒补充 1 个令牌，允许突发 3 个
+	limit := ratelimit.Limit{Algorithm: ratelimit.AlgorithmTokenBucket, Rate: 1, Period: time.Second, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		res, err := rl.Allow(ctx, "users", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, 3, res.Limit)
+		require.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, err := rl.Allow(ctx, "users", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, time.Second, res.RetryAfter)
+	require.Equal(t, 3*time.Second, res.ResetAfter)
+
+	clock.Advance(1500 * time.Millisecond)
+
+	res, err = rl.Allow(ctx, "users", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+
+	res, err = rl.Allow(ctx, "users", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+}
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for _, algorithm := range []string{ratelimit.AlgorithmSlidingWindow, ratelimit.AlgorithmTokenBucket} {
+		rl := ratelimit.NewMemory()
+		limit := ratelimit.Limit{Algorithm: algorithm, Rate: 2, Period: 100 * time.Millisecond}
+
+		for i := 0; i < 2; i++ {
+			res, err := rl.Allow(ctx, "k", limit)
+			require.NoError(t, err)
+			require.True(t, res.Allowed, algorithm)
+			require.Equal(t, 1-i, res.Remaining, algorithm)
+		}
+
+		res, err := rl.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		require.False(t, res.Allowed, algorithm)
+		require.Greater(t, res.RetryAfter, time.Duration(0), algorithm)
+		require.LessOrEqual(t, res.RetryAfter, 100*time.Millisecond, algorithm)
+
+		time.Sleep(res.RetryAfter + 10*time.Millisecond)
+
+		res, err = rl.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed, algorithm)
+	}
+}
+
+func TestInvalidLimit(t *testing.T) {
+	t.Parallel()
+
+	rl, _, _ := newRedis(t)
+
+	_, err := rl.Allow(context.Background(), "k", ratelimit.Limit{Rate: 0, Period: time.Second})
+	require.ErrorIs(t, err, ratelimit.ErrInvalidLimit)
+
+	_, err = ratelimit.NewMemory().Allow(context.Background(), "k",
+		ratelimit.Limit{Algorithm: "fixed-window", Rate: 1, Period: time.Second})
+	require.ErrorIs(t, err, ratelimit.ErrInvalidLimit)
+}
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	primary, _, m := newRedis(t)
+	rl := ratelimit.NewFallback(primary, ratelimit.NewMemory(), logger.New(logger.Config{Level: "error"}))
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 1, Period: time.Minute}
+
+	res, err := rl.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	// Redis 不可用时降级到进程内计数，仍然限流
+	m.Close()
+
+	_, err = primary.Allow(ctx, "k", limit)
+	require.ErrorIs(t, err, ratelimit.ErrStorage)
+
+	res, err = rl.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	res, err = rl.Allow(ctx, "k", limit)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+}
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	rules := ratelimit.NewRules(nil)
+	_, ok := rules.Get("login")
+	require.False(t, ok)
+
+	rules.Store(map[string]ratelimit.Rule{
+		"login": {Limit: ratelimit.Limit{Rate: 5, Period: time.Minute}, Key: "ip"},
+	})
+
+	rule, ok := rules.Get("login")
+	require.True(t, ok)
+	require.Equal(t, 5, rule.Rate)
+	require.Equal(t, "ip", rule.Key)
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const defaultPrefix = "ratelimit:"
+
+//nolint:gochecknoglobals
+var (
+	// KEYS[1] 请求日志 ZSET；ARGV[1] 当前时间(ms)，ARGV[2] 窗口(ms)，ARGV[3] 上限，ARGV[4] 成员.
+	// 返回 {是否允许, 剩余配额, 重试等待(ms), 完全恢复(ms)}.
+	slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+local retry = 0
+local reset = 0
+if count > 0 then
+	local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+	local newest = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+	if allowed == 0 then
+		retry = tonumber(oldest[2]) + window - now
+	end
+	reset = tonumber(newest[2]) + window - now
+	redis.call("PEXPIRE", KEYS[1], window)
+end
+return {allowed, limit - count, retry, reset}
+`)
+
+	// KEYS[1] 令牌桶 HASH；ARGV[1] 当前时间(ms)，ARGV[2] 补充 Rate 个令牌的周期(ms)，ARGV[3] Rate，ARGV[4] 容量.
+	// 返回值同上.
+	tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3]) / period
+local capacity = tonumber(ARGV[4])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.max(reset, 1))
+return {allowed, math.floor(tokens), retry, reset}
+`)
+)
+
+// Redis 在 Redis 中通过 Lua 脚本原子地计数，多实例共享配额.
+type Redis struct {
+	rdb    *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedis -.
+func NewRedis(rdb *redis.Client, opts ...Option) *Redis {
+	r := &Redis{
+		rdb:    rdb,
+		prefix: defaultPrefix,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Allow -.
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if err := limit.validate(); err != nil {
+		return Result{}, err
+	}
+
+	now := r.now().UnixMilli()
+	period := limit.Period.Milliseconds()
+
+	var (
+		reply interface{}
+		err   error
+	)
+
+	if limit.Algorithm == AlgorithmTokenBucket {
+		reply, err = tokenBucketScript.Run(ctx, r.rdb,
+			[]string{r.prefix + "tb:" + key},
+			now, period, limit.Rate, limit.capacity(),
+		).Result()
+	} else {
+		reply, err = slidingWindowScript.Run(ctx, r.rdb,
+			[]string{r.prefix + "sw:" + key},
+			now, period, limit.Rate, strconv.FormatInt(now, 10)+"-"+uuid.NewString(),
+		).Result()
+	}
+
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %s", ErrStorage, err)
+	}
+
+	return parseReply(reply, limit)
+}
+
+func parseReply(reply interface{}, limit Limit) (Result, error) {
+	const fields = 4
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != fields {
+		return Result{}, fmt.Errorf("%w: unexpected reply %v", ErrStorage, reply)
+	}
+
+	n := make([]int64, fields)
+
+	for i, v := range values {
+		if n[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("%w: unexpected reply %v", ErrStorage, reply)
+		}
+	}
+
+	return Result{
+		Allowed:    n[0] == 1,
+		Limit:      limit.capacity(),
+		Remaining:  int(n[1]),
+		RetryAfter: time.Duration(n[2]) * time.Millisecond,
+		ResetAfter: time.Duration(n[3]) * time.Millisecond,
+	}, nil
+}
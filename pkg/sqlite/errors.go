@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/ninehills/go-webapp-template/pkg/mysql"
+)
+
+// UNIQUE constraint failed: user.email.
+//
+//nolint:gochecknoglobals
+var uniqueRegexp = regexp.MustCompile(`UNIQUE constraint failed: ([\w.]+)`)
+
+// Classify - 将 SQLite 错误按 MySQL 错误的分类返回，上层使用相同的方式处理，无法识别时返回 nil.
+// 唯一约束冲突时 Index 为冲突的（第一个）字段名.
+func Classify(err error) *mysql.Error {
+	var se *sqlite.Error
+	if !errors.As(err, &se) {
+		return nil
+	}
+
+	switch se.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		index := ""
+		if match := uniqueRegexp.FindStringSubmatch(se.Error()); match != nil {
+			index = match[1][strings.LastIndex(match[1], ".")+1:]
+		}
+
+		return mysql.NewError(mysql.KindDuplicate, index, err)
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return mysql.NewError(mysql.KindNoReferencedRow, "", err)
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return mysql.NewError(mysql.KindLockWaitTimeout, "", err)
+	}
+
+	return nil
+}
+
+// IsDuplicate - 是否违反唯一约束.
+func IsDuplicate(err error) bool {
+	e := Classify(err)
+
+	return e != nil && e.Kind == mysql.KindDuplicate
+}
@@ -0,0 +1,18 @@
+package sqlite
+
+// Option -.
+type Option func(*SQLite)
+
+// MaxOpenConns -.
+func MaxOpenConns(conns int) Option {
+	return func(s *SQLite) {
+		s.maxOpenConns = conns
+	}
+}
+
+// BusyTimeout - 数据库被锁定时的最长等待时间，单位毫秒.
+func BusyTimeout(ms int) Option {
+	return func(s *SQLite) {
+		s.busyTimeout = ms
+	}
+}
@@ -0,0 +1,85 @@
+// Package sqlite 提供基于文件的 SQLite 数据库，用于本地开发和不依赖外部服务的测试.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	// 纯 Go 实现的 SQLite 驱动，不需要 CGO.
+	_ "modernc.org/sqlite"
+
+	"github.com/ninehills/go-webapp-template/pkg/logger"
+)
+
+const (
+	defaultMaxOpenConns = 4
+	defaultBusyTimeout  = 5000
+	dirPerm             = 0o755
+)
+
+// SQLite -.
+type SQLite struct {
+	maxOpenConns int
+	busyTimeout  int
+
+	DB *sql.DB
+}
+
+// New - path 为数据库文件路径，文件不存在时自动创建.
+func New(l logger.Logger, path string, opts ...Option) (*SQLite, error) {
+	s := &SQLite{
+		maxOpenConns: defaultMaxOpenConns,
+		busyTimeout:  defaultBusyTimeout,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// 外键约束默认关闭；WAL 允许读写并发；写事务使用 BEGIN IMMEDIATE，避免读锁升级为写锁时直接返回 SQLITE_BUSY；
+	// 时间以 UTC 文本写入，与 CURRENT_TIMESTAMP 的格式可以直接比较
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", s.busyTimeout))
+	q.Set("_txlock", "immediate")
+	q.Set("_time_format", "sqlite")
+
+	err := os.MkdirAll(filepath.Dir(path), dirPerm)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite - New - create dir failed: %w", err)
+	}
+
+	s.DB, err = sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("sqlite - New - Open sqlite database failed: %w", err)
+	}
+
+	s.DB.SetMaxOpenConns(s.maxOpenConns)
+
+	err = s.DB.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("sqlite - New - Open %s failed: %w", path, err)
+	}
+
+	l.Infof("sqlite - New - Open %s", path)
+
+	return s, nil
+}
+
+// Ping - 用于健康检查.
+func (s *SQLite) Ping(ctx context.Context) error {
+	return s.DB.PingContext(ctx)
+}
+
+// Close -.
+func (s *SQLite) Close() {
+	if s.DB != nil {
+		s.DB.Close()
+	}
+}
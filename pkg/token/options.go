@@ -0,0 +1,29 @@
+package token
+
+import "time"
+
+// Option -.
+type Option func(*Manager)
+
+// Issuer - 为空时使用默认值.
+func Issuer(issuer string) Option {
+	return func(m *Manager) {
+		if issuer != "" {
+			m.issuer = issuer
+		}
+	}
+}
+
+// AccessTokenTTL -.
+func AccessTokenTTL(seconds int) Option {
+	return func(m *Manager) {
+		m.accessTokenTTL = time.Second * time.Duration(seconds)
+	}
+}
+
+// RefreshTokenTTL -.
+func RefreshTokenTTL(seconds int) Option {
+	return func(m *Manager) {
+		m.refreshTokenTTL = time.Second * time.Duration(seconds)
+	}
+}
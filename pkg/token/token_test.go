@@ -0,0 +1,69 @@
+package token_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ninehills/go-webapp-template/pkg/token"
+)
+
+func TestAccessToken(t *testing.T) {
+	t.Parallel()
+
+	m := token.New("secret", token.Issuer("test"), token.AccessTokenTTL(60))
+
+	signed, claims, err := m.IssueAccessToken("admin")
+	require.NoError(t, err)
+	require.Equal(t, "admin", claims.Subject)
+
+	parsed, err := m.ParseAccessToken(signed)
+	require.NoError(t, err)
+	require.Equal(t, claims.ID, parsed.ID)
+	require.Equal(t, "admin", parsed.Subject)
+
+	tests := []struct {
+		name  string
+		m     *token.Manager
+		token string
+		err   error
+	}{
+		{name: "wrong secret", m: token.New("other", token.Issuer("test")), token: signed, err: token.ErrInvalid},
+		{name: "wrong issuer", m: token.New("secret", token.Issuer("other")), token: signed, err: token.ErrInvalid},
+		{name: "garbage", m: m, token: "not-a-token", err: token.ErrInvalid},
+		{name: "expired", m: m, token: expiredToken(t), err: token.ErrExpired},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.m.ParseAccessToken(tc.token)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	m := token.New("secret")
+
+	a, err := m.NewRefreshToken()
+	require.NoError(t, err)
+
+	b, err := m.NewRefreshToken()
+	require.NoError(t, err)
+	require.NotEqual(t, a, b)
+}
+
+func expiredToken(t *testing.T) string {
+	t.Helper()
+
+	signed, _, err := token.New("secret", token.Issuer("test"), token.AccessTokenTTL(-60)).IssueAccessToken("admin")
+	require.NoError(t, err)
+
+	return signed
+}
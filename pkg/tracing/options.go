@@ -0,0 +1,39 @@
+package tracing
+
+// Option -.
+type Option func(*Tracing)
+
+// Exporter - span 导出方式：none、otlp、stdout、file，空字符串等同于 none.
+func Exporter(exporter string) Option {
+	return func(t *Tracing) {
+		t.exporter = exporter
+	}
+}
+
+// Endpoint - OTLP HTTP 接收端地址，如 localhost:4318，为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT 或默认值.
+func Endpoint(endpoint string) Option {
+	return func(t *Tracing) {
+		t.endpoint = endpoint
+	}
+}
+
+// Insecure - OTLP 使用 HTTP 而不是 HTTPS.
+func Insecure(insecure bool) Option {
+	return func(t *Tracing) {
+		t.insecure = insecure
+	}
+}
+
+// File - file 导出方式的输出文件，span 以 JSON 形式追加写入.
+func File(file string) Option {
+	return func(t *Tracing) {
+		t.file = file
+	}
+}
+
+// SampleRatio - 根 span 的采样率，取值 0~1，上游已采样的请求总是会被采样.
+func SampleRatio(ratio float64) Option {
+	return func(t *Tracing) {
+		t.sampleRatio = ratio
+	}
+}
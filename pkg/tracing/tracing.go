@@ -0,0 +1,130 @@
+// Package tracing configures the OpenTelemetry tracer provider and W3C propagation.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	defaultSampleRatio = 1.0
+)
+
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Tracing 持有全局 TracerProvider，退出时需要调用 Shutdown 将未导出的 span 刷出.
+type Tracing struct {
+	serviceName string
+	exporter    string
+	endpoint    string
+	insecure    bool
+	file        string
+	sampleRatio float64
+
+	provider *sdktrace.TracerProvider
+	closer   io.Closer
+}
+
+// New 初始化全局 TracerProvider 和 W3C traceparent/baggage propagator.
+// exporter 为 none 时不导出 span，但仍会透传上游的 traceparent，日志中依然可以关联 trace id.
+func New(serviceName string, opts ...Option) (*Tracing, error) {
+	t := &Tracing{
+		serviceName: serviceName,
+		exporter:    ExporterNone,
+		sampleRatio: defaultSampleRatio,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exp, err := t.newExporter()
+	if err != nil {
+		return nil, err
+	}
+
+	if exp == nil {
+		return t, nil
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(t.serviceName),
+		)),
+	)
+	otel.SetTracerProvider(t.provider)
+
+	return t, nil
+}
+
+func (t *Tracing) newExporter() (sdktrace.SpanExporter, error) {
+	switch t.exporter {
+	case ExporterNone, "":
+		return nil, nil //nolint:nilnil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if t.endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(t.endpoint))
+		}
+
+		if t.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		f, err := os.OpenFile(t.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gomnd
+		if err != nil {
+			return nil, fmt.Errorf("tracing - open file %s failed: %w", t.file, err)
+		}
+
+		t.closer = f
+
+		return stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExporter, t.exporter)
+	}
+}
+
+// Shutdown 刷出未导出的 span 并关闭 exporter.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	err := t.provider.Shutdown(ctx)
+
+	if t.closer != nil {
+		if cerr := t.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
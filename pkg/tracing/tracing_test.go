@@ -0,0 +1,35 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"github.com/ninehills/go-webapp-template/pkg/tracing"
+)
+
+func TestFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "trace.json")
+
+	tr, err := tracing.New("test", tracing.Exporter(tracing.ExporterFile), tracing.File(file))
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"Name":"test-span"`)
+	require.Contains(t, string(content), span.SpanContext().TraceID().String())
+}
+
+func TestUnknownExporter(t *testing.T) {
+	_, err := tracing.New("test", tracing.Exporter("zipkin"))
+	require.ErrorIs(t, err, tracing.ErrUnknownExporter)
+}
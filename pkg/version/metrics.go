@@ -0,0 +1,34 @@
+package version
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterBuildInfo 注册 build_info 指标，值恒为 1，版本信息放在 label 中，重复注册时忽略.
+func RegisterBuildInfo() error {
+	v := GetVersion()
+
+	g := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "build_info",
+		Help: "Build information of the running binary, the value is always 1.",
+		ConstLabels: prometheus.Labels{
+			"version": v.Version,
+			"commit":  v.Commit,
+			"date":    v.Date,
+			"branch":  v.Branch,
+			"buildBy": v.BuildBy,
+		},
+	})
+	g.Set(1)
+
+	err := prometheus.Register(g)
+
+	var are prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &are) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+-- Role 角色表
+CREATE TABLE IF NOT EXISTS `role` (
+    `id` bigint PRIMARY KEY  NOT NULL AUTO_INCREMENT COMMENT '主键id',
+    `name` varchar(64) UNIQUE KEY NOT NULL DEFAULT '' COMMENT '角色名称',
+    `description` text NOT NULL COMMENT '备注',
+    `created_at` datetime NOT NULL COMMENT '创建时间',
+    `updated_at` datetime NOT NULL COMMENT '更新时间'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT '角色表';
+
+-- RolePermission 角色权限表
+CREATE TABLE IF NOT EXISTS `role_permission` (
+    `id` bigint PRIMARY KEY  NOT NULL AUTO_INCREMENT COMMENT '主键id',
+    `role_id` bigint NOT NULL COMMENT '角色id',
+    `permission` varchar(128) NOT NULL DEFAULT '' COMMENT '权限，格式为 resource:action[:self]，支持 * 通配',
+    `created_at` datetime NOT NULL COMMENT '创建时间',
+    UNIQUE KEY `uk_role_permission` (`role_id`, `permission`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT '角色权限表';
+
+-- UserRole 用户角色关联表
+CREATE TABLE IF NOT EXISTS `user_role` (
+    `id` bigint PRIMARY KEY  NOT NULL AUTO_INCREMENT COMMENT '主键id',
+    `user_id` bigint NOT NULL COMMENT '用户id',
+    `role_id` bigint NOT NULL COMMENT '角色id',
+    `created_at` datetime NOT NULL COMMENT '创建时间',
+    UNIQUE KEY `uk_user_role` (`user_id`, `role_id`),
+    INDEX(`role_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT '用户角色关联表';
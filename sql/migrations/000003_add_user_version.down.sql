@@ -0,0 +1 @@
+ALTER TABLE `user` DROP COLUMN `version`;
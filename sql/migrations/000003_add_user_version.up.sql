@@ -0,0 +1,2 @@
+-- 用户乐观锁版本号，每次更新加一，用于 ETag / If-Match
+ALTER TABLE `user` ADD COLUMN `version` bigint NOT NULL DEFAULT 1 COMMENT '乐观锁版本号';
@@ -0,0 +1,2 @@
+ALTER TABLE `user` DROP INDEX `idx_deleted_at`;
+ALTER TABLE `user` DROP COLUMN `deleted_at`;
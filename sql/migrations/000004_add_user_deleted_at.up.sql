@@ -0,0 +1,3 @@
+-- 用户软删除，超过保留期后由定时任务永久删除
+ALTER TABLE `user` ADD COLUMN `deleted_at` datetime NULL DEFAULT NULL COMMENT '删除时间，NULL 代表未删除';
+ALTER TABLE `user` ADD INDEX `idx_deleted_at` (`deleted_at`);
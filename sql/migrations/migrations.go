@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned schema migrations into the binary.
+//
+// 文件命名规则为 <version>_<name>.up.sql / <version>_<name>.down.sql，
+// sqlc 同样从这里读取表结构（会自动忽略 .down.sql）.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS //nolint:gochecknoglobals
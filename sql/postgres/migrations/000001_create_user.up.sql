@@ -0,0 +1,16 @@
+-- User 用户表
+CREATE TABLE IF NOT EXISTS "user" (
+    id bigserial PRIMARY KEY NOT NULL,
+    username varchar(64) NOT NULL DEFAULT '' UNIQUE,
+    status integer NOT NULL DEFAULT 1,
+    email varchar(255) NOT NULL DEFAULT '' UNIQUE,
+    password varchar(255) NOT NULL DEFAULT '',
+    description text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_user_status ON "user" (status);
+CREATE INDEX IF NOT EXISTS idx_user_created_at ON "user" (created_at);
+COMMENT ON TABLE "user" IS '用户表';
+COMMENT ON COLUMN "user".status IS '用户状态，1=正常，2=禁用';
+COMMENT ON COLUMN "user".password IS '加密后的密码';
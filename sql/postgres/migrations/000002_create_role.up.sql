@@ -0,0 +1,31 @@
+-- Role 角色表
+CREATE TABLE IF NOT EXISTS role (
+    id bigserial PRIMARY KEY NOT NULL,
+    name varchar(64) NOT NULL DEFAULT '' UNIQUE,
+    description text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+COMMENT ON TABLE role IS '角色表';
+
+-- RolePermission 角色权限表
+CREATE TABLE IF NOT EXISTS role_permission (
+    id bigserial PRIMARY KEY NOT NULL,
+    role_id bigint NOT NULL,
+    permission varchar(128) NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL,
+    CONSTRAINT uk_role_permission UNIQUE (role_id, permission)
+);
+COMMENT ON TABLE role_permission IS '角色权限表';
+COMMENT ON COLUMN role_permission.permission IS '权限，格式为 resource:action[:self]，支持 * 通配';
+
+-- UserRole 用户角色关联表
+CREATE TABLE IF NOT EXISTS user_role (
+    id bigserial PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    created_at timestamptz NOT NULL,
+    CONSTRAINT uk_user_role UNIQUE (user_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_role_role_id ON user_role (role_id);
+COMMENT ON TABLE user_role IS '用户角色关联表';
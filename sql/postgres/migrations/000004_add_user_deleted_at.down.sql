@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_deleted_at;
+ALTER TABLE "user" DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+-- 用户软删除，超过保留期后由定时任务永久删除
+ALTER TABLE "user" ADD COLUMN deleted_at timestamptz NULL DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_user_deleted_at ON "user" (deleted_at);
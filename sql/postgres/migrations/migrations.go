@@ -0,0 +1,9 @@
+// Package migrations embeds the PostgreSQL schema migrations into the binary.
+//
+// 与 sql/migrations 中的 MySQL 迁移一一对应，修改表结构时需要同时修改两边.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS //nolint:gochecknoglobals
@@ -0,0 +1,52 @@
+-- name: GetRole :one
+SELECT * FROM role
+WHERE name = $1 LIMIT 1;
+
+-- name: CreateRole :exec
+INSERT INTO role (
+  name, description, created_at, updated_at
+) VALUES (
+  $1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+);
+
+-- name: AddRolePermission :exec
+INSERT INTO role_permission (
+  role_id, permission, created_at
+) VALUES (
+  $1, $2, CURRENT_TIMESTAMP
+) ON CONFLICT DO NOTHING;
+
+-- name: ListRolePermissions :many
+SELECT permission FROM role_permission
+WHERE role_id = $1
+ORDER BY permission;
+
+-- name: AssignUserRole :exec
+INSERT INTO user_role (
+  user_id, role_id, created_at
+) VALUES (
+  $1, $2, CURRENT_TIMESTAMP
+) ON CONFLICT DO NOTHING;
+
+-- name: ListUserRoles :many
+SELECT role.* FROM role
+JOIN user_role ON user_role.role_id = role.id
+WHERE user_role.user_id = $1
+ORDER BY role.name;
+
+-- name: ListUserPermissions :many
+SELECT DISTINCT role_permission.permission FROM role_permission
+JOIN user_role ON user_role.role_id = role_permission.role_id
+JOIN "user" ON "user".id = user_role.user_id
+WHERE "user".username = $1 AND "user".deleted_at IS NULL
+ORDER BY role_permission.permission;
+
+-- name: DeleteUserRoles :exec
+DELETE FROM user_role
+WHERE user_id = $1;
+
+-- name: PurgeDeletedUserRoles :exec
+DELETE FROM user_role
+USING "user"
+WHERE "user".id = user_role.user_id
+  AND "user".deleted_at IS NOT NULL AND "user".deleted_at < sqlc.arg('before')::timestamptz;
@@ -0,0 +1,47 @@
+-- name: GetUser :one
+SELECT * FROM "user"
+WHERE username = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListUser :many
+SELECT * FROM "user"
+WHERE deleted_at IS NULL
+ORDER BY id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CreateUser :exec
+INSERT INTO "user" (
+  username, status, email, password, description, created_at, updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+);
+
+-- name: UpdateUser :execrows
+UPDATE "user"
+SET
+ status = coalesce(sqlc.narg('status'), status),
+ email = coalesce(sqlc.narg('email'), email),
+ password = coalesce(sqlc.narg('password'), password),
+ description = coalesce(sqlc.narg('description'), description),
+ version = version + 1,
+ updated_at = CURRENT_TIMESTAMP
+WHERE username = sqlc.arg('username') AND version = sqlc.arg('version') AND deleted_at IS NULL;
+
+-- name: DeleteUser :exec
+UPDATE "user"
+SET
+ deleted_at = CURRENT_TIMESTAMP,
+ version = version + 1,
+ updated_at = CURRENT_TIMESTAMP
+WHERE username = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE "user"
+SET
+ deleted_at = NULL,
+ version = version + 1,
+ updated_at = CURRENT_TIMESTAMP
+WHERE username = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM "user"
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg('before')::timestamptz;
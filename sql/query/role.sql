@@ -0,0 +1,51 @@
+-- name: GetRole :one
+SELECT * FROM role
+WHERE name = ? LIMIT 1;
+
+-- name: CreateRole :exec
+INSERT INTO role (
+  name, description, created_at, updated_at
+) VALUES (
+  ?, ?, UTC_TIMESTAMP(), UTC_TIMESTAMP()
+);
+
+-- name: AddRolePermission :exec
+INSERT IGNORE INTO role_permission (
+  role_id, permission, created_at
+) VALUES (
+  ?, ?, UTC_TIMESTAMP()
+);
+
+-- name: ListRolePermissions :many
+SELECT permission FROM role_permission
+WHERE role_id = ?
+ORDER BY permission;
+
+-- name: AssignUserRole :exec
+INSERT IGNORE INTO user_role (
+  user_id, role_id, created_at
+) VALUES (
+  ?, ?, UTC_TIMESTAMP()
+);
+
+-- name: ListUserRoles :many
+SELECT role.* FROM role
+JOIN user_role ON user_role.role_id = role.id
+WHERE user_role.user_id = ?
+ORDER BY role.name;
+
+-- name: ListUserPermissions :many
+SELECT DISTINCT role_permission.permission FROM role_permission
+JOIN user_role ON user_role.role_id = role_permission.role_id
+JOIN user ON user.id = user_role.user_id
+WHERE user.username = ? AND user.deleted_at IS NULL
+ORDER BY role_permission.permission;
+
+-- name: DeleteUserRoles :exec
+DELETE FROM user_role
+WHERE user_id = ?;
+
+-- name: PurgeDeletedUserRoles :exec
+DELETE user_role FROM user_role
+JOIN user ON user.id = user_role.user_id
+WHERE user.deleted_at IS NOT NULL AND user.deleted_at < CAST(sqlc.arg('before') AS DATETIME);
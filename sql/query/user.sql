@@ -1,9 +1,10 @@
 -- name: GetUser :one
 SELECT * FROM user
-WHERE username = ? LIMIT 1;
+WHERE username = ? AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListUser :many
 SELECT * FROM user
+WHERE deleted_at IS NULL
 ORDER BY id DESC
 LIMIT ?, ?;
 
@@ -14,16 +15,33 @@ INSERT INTO user (
   ?, ?, ?, ?, ?, UTC_TIMESTAMP(), UTC_TIMESTAMP()
 );
 
--- name: UpdateUser :exec
+-- name: UpdateUser :execrows
 UPDATE user
 SET
  status = coalesce(sqlc.narg('status'), status),
  email = coalesce(sqlc.narg('email'), email),
  password = coalesce(sqlc.narg('password'), password),
  description = coalesce(sqlc.narg('description'), description),
+ version = version + 1,
  updated_at = UTC_TIMESTAMP()
-WHERE username = sqlc.arg('username');
+WHERE username = sqlc.arg('username') AND version = sqlc.arg('version') AND deleted_at IS NULL;
 
 -- name: DeleteUser :exec
+UPDATE user
+SET
+ deleted_at = UTC_TIMESTAMP(),
+ version = version + 1,
+ updated_at = UTC_TIMESTAMP()
+WHERE username = ? AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE user
+SET
+ deleted_at = NULL,
+ version = version + 1,
+ updated_at = UTC_TIMESTAMP()
+WHERE username = ? AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedUsers :execrows
 DELETE FROM user
-WHERE username = ?;
\ No newline at end of file
+WHERE deleted_at IS NOT NULL AND deleted_at < CAST(sqlc.arg('before') AS DATETIME);
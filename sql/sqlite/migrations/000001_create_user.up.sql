@@ -0,0 +1,13 @@
+-- User 用户表
+CREATE TABLE IF NOT EXISTS user (
+    id integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    username varchar(64) NOT NULL DEFAULT '' UNIQUE,
+    status int NOT NULL DEFAULT 1,
+    email varchar(255) NOT NULL DEFAULT '' UNIQUE,
+    password varchar(255) NOT NULL DEFAULT '',
+    description text NOT NULL,
+    created_at datetime NOT NULL,
+    updated_at datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_user_status ON user (status);
+CREATE INDEX IF NOT EXISTS idx_user_created_at ON user (created_at);
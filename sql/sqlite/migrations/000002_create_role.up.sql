@@ -0,0 +1,27 @@
+-- Role 角色表
+CREATE TABLE IF NOT EXISTS role (
+    id integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name varchar(64) NOT NULL DEFAULT '' UNIQUE,
+    description text NOT NULL,
+    created_at datetime NOT NULL,
+    updated_at datetime NOT NULL
+);
+
+-- RolePermission 角色权限表
+CREATE TABLE IF NOT EXISTS role_permission (
+    id integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    role_id bigint NOT NULL,
+    permission varchar(128) NOT NULL DEFAULT '',
+    created_at datetime NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_role_permission ON role_permission (role_id, permission);
+
+-- UserRole 用户角色关联表
+CREATE TABLE IF NOT EXISTS user_role (
+    id integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    created_at datetime NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uk_user_role ON user_role (user_id, role_id);
+CREATE INDEX IF NOT EXISTS idx_user_role_role_id ON user_role (role_id);